@@ -10,45 +10,106 @@ import (
 	"bookcabin-voucher/internal/service"
 	"bookcabin-voucher/internal/usecase"
 	"bookcabin-voucher/internal/validation"
+	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate-layout" {
+		os.Exit(runValidateLayout(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout))
+	}
+
 	// Load environment variables
 	cfg := config.LoadConfig()
 
-	// Connect to SQLite
-	db, err := gorm.Open(sqlite.Open(cfg.DBPath), &gorm.Config{})
+	// Connect to the configured database
+	db, err := persistent.Open(cfg.DBDriver, cfg.DBDSN)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	// Run DB migration
-	migration.Migrate(db)
+	// Schema changes are applied ahead of deploy with `migrate up`
+	if err := migration.Check(context.Background(), db); err != nil {
+		log.Fatalf("%v; run `%s migrate up` first", err, os.Args[0])
+	}
+
+	r, seatGenerator, err := newRouter(cfg, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	watchSeatLayouts(cfg, seatGenerator)
+
+	// Run server
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatalf("failed to start server: %v", err)
+	}
+}
 
+// newRouter wires the handlers to db and the services set up from cfg.
+func newRouter(cfg config.Config, db *gorm.DB) (*gin.Engine, *service.SeatGenerator, error) {
 	// Init dependencies
 	repo := persistent.NewFlightRepository(db)
-	seatGenerator := service.NewSeatAllocator(cfg.SeatLayoutPath)
-	u := usecase.NewFlightUsecase(repo, seatGenerator)
+	var seatSource rand.Source
+	if cfg.SeatRandomSeed != 0 {
+		seatSource = rand.NewSource(cfg.SeatRandomSeed)
+	}
+	seatGenerator, err := service.NewSeatAllocator(cfg.SeatLayoutPath, seatSource, cfg.SeatStrategies)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load seat layouts: %w", err)
+	}
+	voucherSigner, err := service.NewVoucherSigner(cfg.VoucherSigningKeyID, cfg.VoucherSigningKeys, cfg.VoucherVerifyKeys, cfg.VoucherTokenGrace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load voucher signing keys: %w", err)
+	}
+	if voucherSigner == nil {
+		log.Println("No voucher signing key configured, vouchers are issued without a token.")
+	}
+	u := usecase.NewFlightUsecase(repo, seatGenerator, voucherSigner)
 	h := handler.NewFlightHandler(u)
+	voucherRenderer, err := service.NewVoucherRenderer(cfg.VoucherTemplatePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load voucher template: %w", err)
+	}
+	vh := handler.NewVoucherHandler(usecase.NewVoucherUsecase(repo, voucherRenderer, voucherSigner))
 
 	// Setup Gin
 	r := gin.Default()
 	r.Use(middleware.CORSMiddleware([]string{cfg.FrontendURL}))
 	r.Use(middleware.RecoveryMiddleware())
+	r.Use(middleware.RequestIDMiddleware())
 
 	//add custom validation
 
-	validation.RegisterValidators()
+	validation.RegisterValidators(seatGenerator)
 
 	// Register routes
-	http.RegisterRoutes(r, h)
+	http.RegisterRoutes(r, h, vh, middleware.IdempotencyMiddleware(persistent.NewIdempotencyRepository(db), cfg.IdempotencyTTL))
+	return r, seatGenerator, nil
+}
 
-	// Run server
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+// watchSeatLayouts reloads the seat layouts on SIGHUP and, when enabled, on file changes.
+func watchSeatLayouts(cfg config.Config, seatGenerator *service.SeatGenerator) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			seatGenerator.ReloadAndLog("SIGHUP")
+		}
+	}()
+
+	if cfg.SeatLayoutWatch {
+		if err := seatGenerator.Watch(context.Background()); err != nil {
+			log.Printf("seat layout hot-reload disabled: %v", err)
+		}
 	}
 }
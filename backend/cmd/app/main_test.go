@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bookcabin-voucher/config"
+	"bookcabin-voucher/infrastructure/persistent"
+	apiModel "bookcabin-voucher/internal/api/model"
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/migration"
+	"bookcabin-voucher/internal/service"
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testConfig is the shipped configuration on a fresh SQLite database.
+func testConfig(t *testing.T) config.Config {
+	return config.Config{
+		DBDriver:            persistent.DriverSQLite,
+		DBDSN:               filepath.Join(t.TempDir(), "vouchers.db"),
+		SeatLayoutPath:      filepath.Join("..", "..", "data", "layout.json"),
+		VoucherTemplatePath: filepath.Join("..", "..", "data", "voucher_template.json"),
+		IdempotencyTTL:      time.Hour,
+		VoucherTokenGrace:   24 * time.Hour,
+	}
+}
+
+func setupRouter(t *testing.T, cfg config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db, err := persistent.Open(cfg.DBDriver, cfg.DBDSN)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	_, err = migration.Up(t.Context(), db)
+	require.NoError(t, err)
+
+	r, _, err := newRouter(cfg, db)
+	require.NoError(t, err)
+	return r
+}
+
+func serve(r *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+// nextWeek is a flight date whose voucher tokens have not expired yet.
+func nextWeek() string {
+	return time.Now().AddDate(0, 0, 7).Format("02-01-06")
+}
+
+func generate(t *testing.T, r *gin.Engine, date string) dto.GenerateResponse {
+	resp := serve(r, http.MethodPost, "/api/generate",
+		`{"name":"Sarah","id":"98123","flightNumber":"JT692","date":"`+date+`","aircraft":"Airbus 320"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var body dto.GenerateResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.NotEmpty(t, body.Vouchers)
+	return body
+}
+
+func TestNewRouter_SignedVouchers(t *testing.T) {
+	seed, _, err := service.NewVoucherSigningKey()
+	require.NoError(t, err)
+	cfg := testConfig(t)
+	cfg.VoucherSigningKeys = map[string]string{"2025-07": seed}
+	r := setupRouter(t, cfg)
+
+	vouchers := generate(t, r, nextWeek()).Vouchers
+	keys, err := service.ParseVoucherKeySet(serve(r, http.MethodGet, "/.well-known/jwks.json", "").Body.Bytes())
+	require.NoError(t, err)
+	for _, voucher := range vouchers {
+		_, err := service.ParseVoucherToken(voucher.Token, keys)
+		assert.NoError(t, err, voucher.Seat)
+	}
+}
+
+func TestNewRouter_UnsignedVouchers(t *testing.T) {
+	r := setupRouter(t, testConfig(t))
+
+	for _, voucher := range generate(t, r, nextWeek()).Vouchers {
+		assert.Empty(t, voucher.Token, voucher.Seat)
+	}
+
+	// nothing is signed, so there is no key to publish
+	resp := serve(r, http.MethodGet, "/.well-known/jwks.json", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"keys":[]}`, resp.Body.String())
+}
+
+func TestNewRouter_UnsignedTokenQR(t *testing.T) {
+	r := setupRouter(t, testConfig(t))
+	date := nextWeek()
+	seat := generate(t, r, date).Vouchers[0].Seat
+	qr := "/api/flights/JT692/" + date + "/assignment/seats/" + seat + "/qr"
+
+	resp := serve(r, http.MethodGet, qr+"?payload=token", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	var failure apiModel.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &failure))
+	assert.Equal(t, apiModel.CodeTokenSigningDisabled, failure.Code)
+
+	// the voucher code still works
+	resp = serve(r, http.MethodGet, qr+"?payload=code", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+}
+
+func TestNewRouter_UnknownSeatStrategyAircraft(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.SeatStrategies = map[string]string{"Concorde": service.StrategyWindow}
+	db, err := persistent.Open(cfg.DBDriver, cfg.DBDSN)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	_, _, err = newRouter(cfg, db)
+	assert.ErrorContains(t, err, `unknown aircraft "Concorde"`)
+}
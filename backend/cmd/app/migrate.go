@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bookcabin-voucher/config"
+	"bookcabin-voucher/infrastructure/persistent"
+	"bookcabin-voucher/internal/migration"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrate applies, rolls back or lists the schema migrations of the
+// configured database. It returns the process exit code.
+func runMigrate(args []string, out io.Writer) int {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+
+	cfg := config.LoadConfig()
+	db, err := persistent.Open(cfg.DBDriver, cfg.DBDSN)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		ran, err := migration.Up(ctx, db)
+		for _, m := range ran {
+			fmt.Fprintf(out, "applied %s\n", m)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		if len(ran) == 0 {
+			fmt.Fprintln(out, "already up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(out, migrateUsage)
+				return 2
+			}
+		}
+		ran, err := migration.Down(ctx, db, steps)
+		for _, m := range ran {
+			fmt.Fprintf(out, "rolled back %s\n", m)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		if len(ran) == 0 {
+			fmt.Fprintln(out, "nothing to roll back")
+		}
+	case "status":
+		statuses, err := migration.List(ctx, db)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%-20s %s\n", applied, status)
+		}
+	default:
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bookcabin-voucher/config"
+	"bookcabin-voucher/internal/service"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// runValidateLayout checks each layout file and reports every problem found.
+// Without arguments it validates SEAT_LAYOUT_PATH. It returns the process exit code.
+func runValidateLayout(paths []string, out io.Writer) int {
+	if len(paths) == 0 {
+		paths = []string{config.LoadConfig().SeatLayoutPath}
+	}
+
+	status := 0
+	for _, path := range paths {
+		layouts, err := service.LoadLayouts(path)
+		if err == nil {
+			fmt.Fprintf(out, "%s: OK (%d aircraft)\n", path, len(layouts))
+			continue
+		}
+
+		status = 1
+		var layoutErrs service.LayoutErrors
+		if !errors.As(err, &layoutErrs) {
+			fmt.Fprintf(out, "%s: %v\n", path, err)
+			continue
+		}
+		for _, layoutErr := range layoutErrs {
+			fmt.Fprintf(out, "%s: %v\n", path, layoutErr)
+		}
+	}
+	return status
+}
@@ -0,0 +1,125 @@
+// Command voucherctl checks signed voucher tokens without calling the voucher
+// API, given the published keys saved to a file or fetched once from a URL.
+package main
+
+import (
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	usage = `usage: voucherctl verify [-keys <jwks url or file>] <token>
+       voucherctl keygen <key-id>`
+	defaultKeys = "http://localhost:8081/.well-known/jwks.json"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
+
+// run executes a voucherctl command and returns the process exit code.
+func run(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(out, usage)
+		return 2
+	}
+	switch args[0] {
+	case "verify":
+		return runVerify(args[1:], out)
+	case "keygen":
+		return runKeygen(args[1:], out)
+	default:
+		fmt.Fprintln(out, usage)
+		return 2
+	}
+}
+
+// runVerify checks the signature and expiry of a voucher token and prints what it grants.
+func runVerify(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	flags.SetOutput(out)
+	source := flags.String("keys", envOr("VOUCHER_KEYS", defaultKeys), "JSON Web Key Set to verify with, a URL or a file")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		fmt.Fprintln(out, usage)
+		return 2
+	}
+
+	keys, err := loadKeys(*source)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	claims, err := service.ParseVoucherToken(strings.TrimSpace(flags.Arg(0)), keys)
+	if err != nil {
+		fmt.Fprintf(out, "invalid voucher: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(out, "valid voucher")
+	fmt.Fprintf(out, "  code:    %s\n", claims.ID)
+	fmt.Fprintf(out, "  flight:  %s on %s\n", claims.FlightNumber, claims.FlightDate)
+	fmt.Fprintf(out, "  seat:    %s\n", claims.Seat)
+	fmt.Fprintf(out, "  crew ID: %s\n", claims.CrewID)
+	fmt.Fprintf(out, "  expires: %s\n", claims.ExpiresAt.UTC().Format(time.RFC3339))
+	return 0
+}
+
+// runKeygen prints a new signing key in the format of the server configuration.
+func runKeygen(args []string, out io.Writer) int {
+	if len(args) != 1 || strings.ContainsAny(args[0], ":, ") {
+		fmt.Fprintln(out, usage)
+		return 2
+	}
+	seed, public, err := service.NewVoucherSigningKey()
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	fmt.Fprintf(out, "# add to VOUCHER_SIGNING_KEYS and set VOUCHER_SIGNING_KEY_ID=%s to sign with it\n", args[0])
+	fmt.Fprintf(out, "%s:%s\n", args[0], seed)
+	fmt.Fprintln(out, "# once retired, move it to VOUCHER_VERIFY_KEYS as")
+	fmt.Fprintf(out, "%s:%s\n", args[0], public)
+	return 0
+}
+
+// loadKeys reads a key set from a URL or, for offline use, a file.
+func loadKeys(source string) ([]model.VoucherKey, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetch(source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keys from %s: %w", source, err)
+	}
+	return service.ParseVoucherKeySet(data)
+}
+
+func fetch(url string) ([]byte, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -5,14 +5,32 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Env            string
-	Port           string
-	FrontendURL    string
-	DBPath         string
-	SeatLayoutPath string
+	Env             string
+	Port            string
+	FrontendURL     string
+	DBDriver        string // sqlite, postgres or mysql
+	DBDSN           string // connection string, the DB_PATH file for SQLite when unset
+	SeatLayoutPath  string
+	SeatLayoutWatch bool  // reload the layout file when it changes on disk
+	SeatRandomSeed  int64 // 0 seeds seat allocation from the clock
+	// SeatStrategies maps an aircraft name or alias to its default seat strategy,
+	// read from SEAT_STRATEGIES as "ATR:front-to-back,Airbus 320:aisle".
+	SeatStrategies      map[string]string
+	IdempotencyTTL      time.Duration // how long a generate response is replayed for its Idempotency-Key
+	VoucherTemplatePath string        // layout of the printed PDF voucher
+	// VoucherSigningKeys maps a key ID to a base64 Ed25519 seed, read from
+	// VOUCHER_SIGNING_KEYS as "2025-07:<seed>,2025-01:<seed>". Tokens are signed
+	// with VoucherSigningKeyID and verify with any of them or VoucherVerifyKeys,
+	// the base64 public keys of retired keys in the same format.
+	VoucherSigningKeys  map[string]string
+	VoucherSigningKeyID string
+	VoucherVerifyKeys   map[string]string
+	VoucherTokenGrace   time.Duration // how long a voucher token stays valid after its flight date
 }
 
 func LoadConfig() Config {
@@ -20,18 +38,50 @@ func LoadConfig() Config {
 	viper.SetConfigFile(filepath.Join(root, "app.env"))
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("DB_DRIVER", "sqlite")
+	viper.SetDefault("VOUCHER_TEMPLATE_PATH", "data/voucher_template.json")
+	viper.SetDefault("VOUCHER_TOKEN_GRACE", "24h")
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Println("No app.env file found or failed to load, using system env if available.")
 	}
 
+	dsn := viper.GetString("DB_DSN")
+	if dsn == "" && viper.GetString("DB_DRIVER") == "sqlite" {
+		dsn = filepath.Join(root, viper.GetString("DB_PATH"))
+	}
+
 	return Config{
-		Env:            viper.GetString("ENV"),
-		Port:           viper.GetString("PORT"),
-		FrontendURL:    viper.GetString("FRONTEND_URL"),
-		DBPath:         filepath.Join(root, viper.GetString("DB_PATH")),
-		SeatLayoutPath: filepath.Join(root, viper.GetString("SEAT_LAYOUT_PATH")),
+		Env:                 viper.GetString("ENV"),
+		Port:                viper.GetString("PORT"),
+		FrontendURL:         viper.GetString("FRONTEND_URL"),
+		DBDriver:            viper.GetString("DB_DRIVER"),
+		DBDSN:               dsn,
+		SeatLayoutPath:      filepath.Join(root, viper.GetString("SEAT_LAYOUT_PATH")),
+		SeatLayoutWatch:     viper.GetBool("SEAT_LAYOUT_WATCH"),
+		SeatRandomSeed:      viper.GetInt64("SEAT_RANDOM_SEED"),
+		SeatStrategies:      parsePairs(viper.GetString("SEAT_STRATEGIES")),
+		IdempotencyTTL:      viper.GetDuration("IDEMPOTENCY_TTL"),
+		VoucherTemplatePath: filepath.Join(root, viper.GetString("VOUCHER_TEMPLATE_PATH")),
+		VoucherSigningKeys:  parsePairs(viper.GetString("VOUCHER_SIGNING_KEYS")),
+		VoucherSigningKeyID: viper.GetString("VOUCHER_SIGNING_KEY_ID"),
+		VoucherVerifyKeys:   parsePairs(viper.GetString("VOUCHER_VERIFY_KEYS")),
+		VoucherTokenGrace:   viper.GetDuration("VOUCHER_TOKEN_GRACE"),
+	}
+}
+
+// parsePairs reads a comma separated list of key:value pairs, ignoring blanks.
+func parsePairs(raw string) map[string]string {
+	pairs := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
 	}
+	return pairs
 }
 
 func findProjectRootWithEnv() string {
@@ -0,0 +1,65 @@
+package persistent
+
+import (
+	"fmt"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+// sqliteBusyTimeout is how long, in milliseconds, a SQLite connection waits
+// for another one's write lock before giving up with SQLITE_BUSY.
+const sqliteBusyTimeout = 30000
+
+// Open connects to the database for driver. The dsn is a file path for SQLite,
+// a URL or key=value string for PostgreSQL and a go-sql-driver DSN for MySQL,
+// which must set parseTime=true.
+func Open(driver, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverSQLite:
+		dialector = sqlite.Open(sqliteDSN(dsn))
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	case DriverMySQL:
+		dialector = mysql.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q, want %s, %s or %s", driver, DriverSQLite, DriverPostgres, DriverMySQL)
+	}
+
+	// TranslateError reports unique violations as gorm.ErrDuplicatedKey on every driver
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s database: %w", driver, err)
+	}
+	return db, nil
+}
+
+// sqliteDSN makes every SQLite transaction take the write lock when it begins
+// (BEGIN IMMEDIATE) and wait for it when another connection holds it. With
+// deferred transactions a reader that later writes fails at once with
+// "database is locked" instead of waiting. Options already in dsn are kept.
+func sqliteDSN(dsn string) string {
+	path, query, _ := strings.Cut(dsn, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return dsn
+	}
+	if !params.Has("_txlock") {
+		params.Set("_txlock", "immediate")
+	}
+	if !params.Has("_busy_timeout") && !params.Has("_timeout") {
+		params.Set("_busy_timeout", strconv.Itoa(sqliteBusyTimeout))
+	}
+	return path + "?" + params.Encode()
+}
@@ -4,8 +4,15 @@ import (
 	"bookcabin-voucher/internal/dto"
 	"bookcabin-voucher/internal/model"
 	"bookcabin-voucher/internal/repository"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type flightRepository struct {
@@ -16,74 +23,288 @@ func NewFlightRepository(db *gorm.DB) repository.FlightRepository {
 	return &flightRepository{db: db}
 }
 
-func (r *flightRepository) BeginTx() *gorm.DB {
-	return r.db.Begin()
+func (r *flightRepository) WithinTx(ctx context.Context, fn func(repo repository.FlightRepository) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&flightRepository{db: tx})
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) || isRetryable(err) {
+		return fmt.Errorf("%w: %w", repository.ErrConflict, err)
+	}
+	return err
+}
+
+// isRetryable reports a transaction the database gave up on because of a
+// concurrent one, which the caller may retry like any other conflict: a SQLite
+// write lock not taken within the busy timeout, a PostgreSQL serialization
+// failure or deadlock, or a MySQL deadlock or lock wait timeout.
+func isRetryable(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+	return false
 }
 
-func (r *flightRepository) CountByFlightAndDate(flightNumber, date string) int64 {
-	return r.CountByFlightAndDateTx(r.db, flightNumber, date)
+// LockFlight upserts the flight's lock row. The update takes the row's write
+// lock, so a second transaction for the flight waits here until the first ends.
+func (r *flightRepository) LockFlight(ctx context.Context, flightNumber, date string) error {
+	lock := model.FlightLock{FlightNumber: flightNumber, FlightDate: date, Version: 1}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "flight_number"}, {Name: "flight_date"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"version":    gorm.Expr("flight_locks.version + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&lock).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock flight %s on %s: %w", flightNumber, date, err)
+	}
+	return nil
 }
 
-func (r *flightRepository) CountByFlightAndDateTx(tx *gorm.DB, flightNumber, date string) int64 {
+func (r *flightRepository) CountByFlightAndDate(ctx context.Context, flightNumber, date string) int64 {
 	var count int64
-	tx.Model(&model.FlightAssignment{}).
+	r.db.WithContext(ctx).Model(&model.FlightAssignment{}).
 		Where("flight_number = ? AND flight_date = ?", flightNumber, date).
 		Count(&count)
 	return count
 }
 
-func (r *flightRepository) GetByFilter(filter dto.FlightFilter) ([]model.FlightAssignment, error) {
-	return r.GetByFilterTx(r.db, filter)
-}
-
-func (r *flightRepository) GetByFilterTx(tx *gorm.DB, filter dto.FlightFilter) ([]model.FlightAssignment, error) {
+func (r *flightRepository) GetByFilter(ctx context.Context, filter dto.FlightFilter) ([]model.FlightAssignment, error) {
 	var assignments []model.FlightAssignment
 
-	query := tx.
+	query := r.db.WithContext(ctx).
 		Model(&model.FlightAssignment{}).
 		Preload("SeatAssignments").
-		Joins("JOIN flight_seat_assignments ON flight_assignments.id = flight_seat_assignments.flight_assignment_id").
-		Where("flight_assignments.flight_number = ? AND flight_assignments.flight_date = ?", filter.FlightNumber, filter.Date)
+		Where("flight_number = ? AND flight_date = ?", filter.FlightNumber, filter.Date)
 
 	if len(filter.Seats) > 0 {
-		query = query.Where("flight_seat_assignments.seat IN ?", filter.Seats)
+		held := r.db.Model(&model.FlightSeatAssignment{}).Select("flight_assignment_id").Where("seat IN ?", filter.Seats)
+		query = query.Where("id IN (?)", held)
 	}
 
-	err := query.
-		Group("flight_assignments.id").
-		Order("flight_seat_assignments.created_at DESC").
-		Find(&assignments).Error
+	err := query.Order("id DESC").Find(&assignments).Error
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to query flight assignments in transaction: %w", err)
+		return nil, fmt.Errorf("failed to query flight assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+var assignmentSortColumns = map[dto.AssignmentSort]string{
+	dto.SortByCreatedAt:    "id", // ids are assigned in creation order
+	dto.SortByFlightDate:   "flight_day",
+	dto.SortByFlightNumber: "flight_number",
+}
+
+func (r *flightRepository) ListAssignments(ctx context.Context, filter dto.AssignmentFilter) ([]model.FlightAssignment, error) {
+	query := r.db.WithContext(ctx).Model(&model.FlightAssignment{}).Preload("SeatAssignments")
+
+	if filter.FromDay != "" {
+		query = query.Where("flight_day >= ?", filter.FromDay)
+	}
+	if filter.ToDay != "" {
+		query = query.Where("flight_day <= ?", filter.ToDay)
+	}
+	if filter.CrewID != "" {
+		query = query.Where("crew_id = ?", filter.CrewID)
+	}
+	for _, prefix := range filter.FlightNumberPrefix {
+		query = query.Where("flight_number LIKE ?", prefix+"%")
+	}
+	if filter.Aircraft != "" {
+		query = query.Where("aircraft_type = ?", filter.Aircraft)
+	}
+
+	column, ok := assignmentSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q", filter.Sort)
+	}
+	op, dir := ">", "ASC"
+	if filter.Descending {
+		op, dir = "<", "DESC"
 	}
 
+	if after := filter.After; after != nil {
+		if column == "id" {
+			query = query.Where("id "+op+" ?", after.ID)
+		} else {
+			query = query.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", after.Value, after.Value, after.ID)
+		}
+	}
+	if column != "id" {
+		query = query.Order(column + " " + dir)
+	}
+
+	var assignments []model.FlightAssignment
+	err := query.Order("id " + dir).Limit(filter.Limit).Find(&assignments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flight assignments: %w", err)
+	}
 	return assignments, nil
 }
 
-func (r *flightRepository) DeleteSeatsByFilterTx(tx *gorm.DB, filter dto.FlightFilter) (int64, error) {
+// DeleteSeatsByFilter retires the re-rolled seats of a flight, recording actor
+// as having revoked them for the re-roll.
+func (r *flightRepository) DeleteSeatsByFilter(ctx context.Context, filter dto.FlightFilter, actor string) (int64, error) {
 	var assignment model.FlightAssignment
-	if err := tx.Where("flight_number = ? AND flight_date = ?", filter.FlightNumber, filter.Date).
+	if err := r.db.WithContext(ctx).Where("flight_number = ? AND flight_date = ?", filter.FlightNumber, filter.Date).
 		First(&assignment).Error; err != nil {
 		return 0, err
 	}
 
-	result := tx.Where("flight_assignment_id = ? AND seat IN ?", assignment.ID, filter.Seats).
-		Delete(&model.FlightSeatAssignment{})
+	result := r.db.WithContext(ctx).Model(&model.FlightSeatAssignment{}).
+		Where("flight_assignment_id = ? AND seat IN ?", assignment.ID, filter.Seats).
+		Updates(revocation(actor, "re-roll"))
 
 	return result.RowsAffected, result.Error
 }
 
-func (r *flightRepository) BulkCreateSeatAssignmentsTx(tx *gorm.DB, seats []model.FlightSeatAssignment) error {
+func (r *flightRepository) BulkCreateSeatAssignments(ctx context.Context, seats []model.FlightSeatAssignment) error {
 	if len(seats) == 0 {
 		return nil
 	}
-	return tx.Create(&seats).Error
+	return r.db.WithContext(ctx).Create(&seats).Error
+}
+
+func (r *flightRepository) RevokeAssignment(ctx context.Context, assignmentID uint, actor, reason string) error {
+	if err := r.RevokeSeats(ctx, assignmentID, nil, actor, reason); err != nil {
+		return err
+	}
+	err := r.db.WithContext(ctx).Model(&model.FlightAssignment{}).
+		Where("id = ?", assignmentID).
+		Updates(revocation(actor, reason)).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke flight assignment: %w", err)
+	}
+	return nil
+}
+
+// RevokeSeats revokes the given seats of an assignment, or all of its seats when seats is empty.
+func (r *flightRepository) RevokeSeats(ctx context.Context, assignmentID uint, seats []string, actor, reason string) error {
+	query := r.db.WithContext(ctx).Model(&model.FlightSeatAssignment{}).Where("flight_assignment_id = ?", assignmentID)
+	if len(seats) > 0 {
+		query = query.Where("seat IN ?", seats)
+	}
+	if err := query.Updates(revocation(actor, reason)).Error; err != nil {
+		return fmt.Errorf("failed to revoke seat assignments: %w", err)
+	}
+	return nil
 }
 
-func (r *flightRepository) CreateTx(tx *gorm.DB, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
-	if err := tx.Create(assignment).Error; err != nil {
+// revocation soft-deletes a row while recording who revoked it and why.
+func revocation(actor, reason string) map[string]any {
+	return map[string]any{
+		"revoked_by":    actor,
+		"revoke_reason": reason,
+		"deleted_at":    time.Now(),
+	}
+}
+
+func (r *flightRepository) Create(ctx context.Context, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
+	if err := r.db.WithContext(ctx).Create(assignment).Error; err != nil {
 		return nil, err
 	}
 	return assignment, nil
 }
+
+func (r *flightRepository) AppendEvent(ctx context.Context, event *model.FlightAssignmentEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record assignment event: %w", err)
+	}
+	return nil
+}
+
+func (r *flightRepository) ListEvents(ctx context.Context, flightNumber, date string) ([]model.FlightAssignmentEvent, error) {
+	var events []model.FlightAssignmentEvent
+	err := r.db.WithContext(ctx).
+		Where("flight_number = ? AND flight_date = ?", flightNumber, date).
+		Order("id").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignment events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *flightRepository) FindVoucher(ctx context.Context, code string) (*model.IssuedVoucher, error) {
+	var voucher model.IssuedVoucher
+	err := r.db.WithContext(ctx).Unscoped().Where("code = ?", code).First(&voucher.Seat).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query voucher: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Unscoped().First(&voucher.Assignment, voucher.Seat.FlightAssignmentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to query voucher assignment: %w", err)
+	}
+
+	var redemptions []model.VoucherRedemption
+	err = r.db.WithContext(ctx).Where("flight_seat_assignment_id = ?", voucher.Seat.ID).Limit(1).Find(&redemptions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query voucher redemption: %w", err)
+	}
+	if len(redemptions) > 0 {
+		voucher.Redemption = &redemptions[0]
+	}
+	return &voucher, nil
+}
+
+func (r *flightRepository) RedeemVoucher(ctx context.Context, redemption *model.VoucherRedemption) error {
+	if err := r.db.WithContext(ctx).Create(redemption).Error; err != nil {
+		return fmt.Errorf("failed to redeem voucher: %w", err)
+	}
+	return nil
+}
+
+func (r *flightRepository) GetSeatInventory(ctx context.Context, flightNumber, date string) ([]model.FlightSeatInventory, error) {
+	var inventory []model.FlightSeatInventory
+	err := r.db.WithContext(ctx).
+		Where("flight_number = ? AND flight_date = ?", flightNumber, date).
+		Order("seat").
+		Find(&inventory).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seat inventory: %w", err)
+	}
+	return inventory, nil
+}
+
+func (r *flightRepository) FindOccupiedSeats(ctx context.Context, flightNumber, date string) ([]string, error) {
+	seats := make([]string, 0)
+	err := r.db.WithContext(ctx).Model(&model.FlightSeatInventory{}).
+		Where("flight_number = ? AND flight_date = ? AND status <> ?", flightNumber, date, model.SeatFree).
+		Pluck("seat", &seats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query occupied seats: %w", err)
+	}
+	return seats, nil
+}
+
+func (r *flightRepository) SetSeatStatus(ctx context.Context, flightNumber, date string, seats []string, status model.SeatStatus) error {
+	if len(seats) == 0 {
+		return nil
+	}
+	rows := make([]model.FlightSeatInventory, 0, len(seats))
+	for _, seat := range seats {
+		rows = append(rows, model.FlightSeatInventory{
+			FlightNumber: flightNumber,
+			FlightDate:   date,
+			Seat:         seat,
+			Status:       status,
+		})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "flight_number"}, {Name: "flight_date"}, {Name: "seat"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+	}).Create(&rows).Error
+}
@@ -0,0 +1,420 @@
+package persistent
+
+import (
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/migration"
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/repository"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testDatabases are the backends the repository tests run against. SQLite runs
+// in memory every time; PostgreSQL and MySQL run when the variable names a
+// throwaway database, e.g. TEST_POSTGRES_DSN=postgres://localhost/voucher_test
+// or TEST_MYSQL_DSN="root@tcp(localhost:3306)/voucher_test?parseTime=true".
+// Neither has an embedded stand-in; CI sets both against service containers.
+var testDatabases = []struct {
+	driver string
+	dsnEnv string
+}{
+	{DriverSQLite, ""},
+	{DriverPostgres, "TEST_POSTGRES_DSN"},
+	{DriverMySQL, "TEST_MYSQL_DSN"},
+}
+
+// forEachDatabase runs test once per backend, each time on a freshly migrated schema.
+func forEachDatabase(t *testing.T, test func(t *testing.T, db *gorm.DB)) {
+	for _, backend := range testDatabases {
+		t.Run(backend.driver, func(t *testing.T) {
+			dsn := ":memory:"
+			if backend.dsnEnv != "" {
+				if dsn = os.Getenv(backend.dsnEnv); dsn == "" {
+					t.Skipf("%s is not set", backend.dsnEnv)
+				}
+			}
+			db, err := Open(backend.driver, dsn)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				if sqlDB, err := db.DB(); err == nil {
+					sqlDB.Close()
+				}
+			})
+
+			// server databases outlive the test, so start from empty tables
+			require.NoError(t, db.Migrator().DropTable(
+				&model.VoucherRedemption{}, &model.FlightSeatAssignment{}, &model.FlightAssignment{}, &model.FlightSeatInventory{},
+				&model.FlightAssignmentEvent{}, &model.IdempotencyRecord{}, &model.FlightLock{}, "schema_migrations",
+			))
+			_, err = migration.Up(t.Context(), db)
+			require.NoError(t, err)
+			test(t, db)
+		})
+	}
+}
+
+func TestListAssignments(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+
+		for _, a := range []model.FlightAssignment{
+			{CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", FlightDay: "2025-07-05", AircraftType: "ATR"},
+			{CrewID: "2", FlightNumber: "GA100", FlightDate: "01-07-25", FlightDay: "2025-07-01", AircraftType: "Airbus 320"},
+			{CrewID: "1", FlightNumber: "JT101", FlightDate: "05-07-25", FlightDay: "2025-07-05", AircraftType: "Airbus 320"},
+			{CrewID: "1", FlightNumber: "JT692", FlightDate: "02-08-25", FlightDay: "2025-08-02", AircraftType: "ATR"},
+		} {
+			require.NoError(t, db.Create(&a).Error)
+		}
+
+		ids := func(assignments []model.FlightAssignment) []uint {
+			out := make([]uint, 0, len(assignments))
+			for _, a := range assignments {
+				out = append(out, a.ID)
+			}
+			return out
+		}
+
+		all, err := repo.ListAssignments(t.Context(), dto.AssignmentFilter{Sort: dto.SortByCreatedAt, Descending: true, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{4, 3, 2, 1}, ids(all))
+
+		july, err := repo.ListAssignments(t.Context(), dto.AssignmentFilter{
+			FromDay: "2025-07-01", ToDay: "2025-07-31", CrewID: "1", FlightNumberPrefix: []string{"JT"},
+			Sort: dto.SortByFlightDate, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1, 3}, ids(july))
+
+		// ties on the sort column are broken by id, so paging never repeats or skips a row
+		first, err := repo.ListAssignments(t.Context(), dto.AssignmentFilter{Sort: dto.SortByFlightDate, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{2, 1}, ids(first))
+
+		rest, err := repo.ListAssignments(t.Context(), dto.AssignmentFilter{
+			Sort: dto.SortByFlightDate, Limit: 10, After: &dto.AssignmentCursor{Value: "2025-07-05", ID: 1},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{3, 4}, ids(rest))
+
+		atr, err := repo.ListAssignments(t.Context(), dto.AssignmentFilter{Aircraft: "ATR", Sort: dto.SortByFlightNumber, Descending: true, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{4, 1}, ids(atr))
+	})
+}
+
+func TestWithinTx_RollsBack(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+		assignment := func() *model.FlightAssignment {
+			return &model.FlightAssignment{CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR"}
+		}
+
+		failed := errors.New("seat generation failed")
+		err := repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+			_, err := tx.Create(t.Context(), assignment())
+			require.NoError(t, err)
+			return failed
+		})
+		assert.ErrorIs(t, err, failed)
+		assert.Zero(t, repo.CountByFlightAndDate(t.Context(), "JT692", "05-07-25"))
+
+		assert.Panics(t, func() {
+			_ = repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+				_, err := tx.Create(t.Context(), assignment())
+				require.NoError(t, err)
+				panic("boom")
+			})
+		})
+		assert.Zero(t, repo.CountByFlightAndDate(t.Context(), "JT692", "05-07-25"))
+
+		require.NoError(t, repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+			_, err := tx.Create(t.Context(), assignment())
+			return err
+		}))
+		assert.EqualValues(t, 1, repo.CountByFlightAndDate(t.Context(), "JT692", "05-07-25"))
+	})
+}
+
+func TestWithinTx_ReportsConflicts(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+		create := func() error {
+			return repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+				if err := tx.LockFlight(t.Context(), "JT692", "05-07-25"); err != nil {
+					return err
+				}
+				_, err := tx.Create(t.Context(), &model.FlightAssignment{CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR"})
+				return err
+			})
+		}
+
+		require.NoError(t, create())
+		assert.ErrorIs(t, create(), repository.ErrConflict)
+
+		version := func() int64 {
+			var lock model.FlightLock
+			require.NoError(t, db.First(&lock, "flight_number = ? AND flight_date = ?", "JT692", "05-07-25").Error)
+			return lock.Version
+		}
+		// the losing transaction's bump was rolled back with it
+		assert.EqualValues(t, 1, version())
+		require.NoError(t, repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+			return tx.LockFlight(t.Context(), "JT692", "05-07-25")
+		}))
+		assert.EqualValues(t, 2, version())
+	})
+}
+
+func TestWithinTx_SQLiteWriteLock(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "vouchers.db")
+	holder, err := Open(DriverSQLite, dsn)
+	require.NoError(t, err)
+	_, err = migration.Up(t.Context(), holder)
+	require.NoError(t, err)
+	waiter, err := Open(DriverSQLite, dsn+"?_busy_timeout=50")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		for _, db := range []*gorm.DB{holder, waiter} {
+			if sqlDB, err := db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
+	})
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = NewFlightRepository(holder).WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	defer close(release)
+
+	// the write lock is taken when the transaction begins, so a second writer
+	// waits out its busy timeout and reports a conflict
+	err = NewFlightRepository(waiter).WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+		return tx.LockFlight(t.Context(), "JT692", "05-07-25")
+	})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestIsRetryable(t *testing.T) {
+	for name, tt := range map[string]struct {
+		err  error
+		want bool
+	}{
+		"sqlite busy":             {sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		"sqlite constraint":       {sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+		"postgres serialization":  {&pgconn.PgError{Code: "40001"}, true},
+		"postgres deadlock":       {&pgconn.PgError{Code: "40P01"}, true},
+		"postgres unique":         {&pgconn.PgError{Code: "23505"}, false},
+		"mysql deadlock":          {&mysql.MySQLError{Number: 1213}, true},
+		"mysql lock wait timeout": {&mysql.MySQLError{Number: 1205}, true},
+		"mysql duplicate":         {&mysql.MySQLError{Number: 1062}, false},
+		"other":                   {errors.New("boom"), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isRetryable(fmt.Errorf("failed to lock flight: %w", tt.err)))
+		})
+	}
+}
+
+func TestRevokeAssignment_AllowsRegeneration(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+
+		create := func() *model.FlightAssignment {
+			var assignment *model.FlightAssignment
+			require.NoError(t, repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+				var err error
+				assignment, err = tx.Create(t.Context(), &model.FlightAssignment{
+					CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR",
+				})
+				if err != nil {
+					return err
+				}
+				return tx.BulkCreateSeatAssignments(t.Context(), []model.FlightSeatAssignment{
+					{FlightAssignmentID: assignment.ID, Seat: "3B"},
+					{FlightAssignmentID: assignment.ID, Seat: "7C"},
+				})
+			}))
+			return assignment
+		}
+		flight := dto.FlightFilter{FlightNumber: "JT692", Date: "05-07-25"}
+
+		first := create()
+		require.NoError(t, repo.RevokeSeats(t.Context(), first.ID, []string{"3B"}, "ops", "crew change"))
+
+		assignments, err := repo.GetByFilter(t.Context(), flight)
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.Equal(t, []string{"7C"}, []string{assignments[0].SeatAssignments[0].Seat})
+
+		require.NoError(t, repo.RevokeAssignment(t.Context(), first.ID, "ops", "flight cancelled"))
+		assert.Zero(t, repo.CountByFlightAndDate(t.Context(), "JT692", "05-07-25"))
+
+		// the revoked rows stay behind with who revoked them and why
+		var revoked []model.FlightSeatAssignment
+		require.NoError(t, db.Unscoped().Where("flight_assignment_id = ?", first.ID).Order("seat").Find(&revoked).Error)
+		require.Len(t, revoked, 2)
+		assert.Equal(t, "crew change", revoked[0].RevokeReason)
+		assert.Equal(t, "flight cancelled", revoked[1].RevokeReason)
+
+		second := create()
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.EqualValues(t, 1, repo.CountByFlightAndDate(t.Context(), "JT692", "05-07-25"))
+	})
+}
+
+func TestDeleteSeatsByFilter_RecordsReRoll(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+
+		var assignment *model.FlightAssignment
+		require.NoError(t, repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+			var err error
+			assignment, err = tx.Create(t.Context(), &model.FlightAssignment{
+				CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR",
+			})
+			if err != nil {
+				return err
+			}
+			return tx.BulkCreateSeatAssignments(t.Context(), []model.FlightSeatAssignment{
+				{FlightAssignmentID: assignment.ID, Seat: "3B"},
+				{FlightAssignmentID: assignment.ID, Seat: "7C"},
+			})
+		}))
+
+		deleted, err := repo.DeleteSeatsByFilter(t.Context(), dto.FlightFilter{
+			FlightNumber: "JT692", Date: "05-07-25", Seats: []string{"3B"},
+		}, "98123")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		// the re-rolled seat is retired, saying who re-rolled it
+		var seats []model.FlightSeatAssignment
+		require.NoError(t, db.Unscoped().Where("flight_assignment_id = ?", assignment.ID).Order("seat").Find(&seats).Error)
+		require.Len(t, seats, 2)
+		assert.True(t, seats[0].DeletedAt.Valid)
+		assert.Equal(t, "98123", seats[0].RevokedBy)
+		assert.Equal(t, "re-roll", seats[0].RevokeReason)
+		assert.False(t, seats[1].DeletedAt.Valid)
+		assert.Empty(t, seats[1].RevokedBy)
+	})
+}
+
+func TestAssignmentEvents_AppendOnly(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+
+		require.NoError(t, repo.AppendEvent(t.Context(), &model.FlightAssignmentEvent{
+			FlightAssignmentID: 1, FlightNumber: "JT692", FlightDate: "05-07-25",
+			Type: model.EventCreated, SeatsTo: []string{"3B", "7C"}, Actor: "270123",
+		}))
+		require.NoError(t, repo.AppendEvent(t.Context(), &model.FlightAssignmentEvent{
+			FlightAssignmentID: 1, FlightNumber: "JT692", FlightDate: "05-07-25",
+			Type: model.EventReRolled, SeatsFrom: []string{"7C"}, SeatsTo: []string{"9A"}, Actor: "270123",
+		}))
+
+		events, err := repo.ListEvents(t.Context(), "JT692", "05-07-25")
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.EventCreated, events[0].Type)
+		assert.Equal(t, []string{"7C"}, events[1].SeatsFrom)
+		assert.Equal(t, []string{"9A"}, events[1].SeatsTo)
+
+		assert.Error(t, db.Model(&events[0]).Update("actor", "someone else").Error)
+		assert.Error(t, db.Delete(&events[0]).Error)
+	})
+}
+
+func TestSeatAssignments_UniqueVoucherCodes(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+		var assignmentID uint
+		issue := func(seats ...model.FlightSeatAssignment) error {
+			return repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+				for i := range seats {
+					seats[i].FlightAssignmentID = assignmentID
+				}
+				return tx.BulkCreateSeatAssignments(t.Context(), seats)
+			})
+		}
+		assignment, err := repo.Create(t.Context(), &model.FlightAssignment{CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR"})
+		require.NoError(t, err)
+		assignmentID = assignment.ID
+
+		// seats from before voucher codes have none, and any number of them may coexist
+		require.NoError(t, issue(model.FlightSeatAssignment{Seat: "1A"}, model.FlightSeatAssignment{Seat: "1B"}))
+		require.NoError(t, issue(model.FlightSeatAssignment{Seat: "3B", Code: "R7X6-5JZ3-03J1"}))
+
+		// a revoked seat keeps its code, so the code is never handed out again
+		require.NoError(t, repo.RevokeSeats(t.Context(), assignmentID, []string{"3B"}, "ops", "re-rolled"))
+		assert.ErrorIs(t, issue(model.FlightSeatAssignment{Seat: "7C", Code: "R7X6-5JZ3-03J1"}), repository.ErrConflict)
+
+		assignments, err := repo.GetByFilter(t.Context(), dto.FlightFilter{FlightNumber: "JT692", Date: "05-07-25"})
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		codes := make(map[string]string)
+		for _, seat := range assignments[0].SeatAssignments {
+			codes[seat.Seat] = seat.Code
+		}
+		assert.Equal(t, map[string]string{"1A": "", "1B": ""}, codes)
+	})
+}
+
+func TestFindVoucher_AndRedeem(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewFlightRepository(db)
+		assignment, err := repo.Create(t.Context(), &model.FlightAssignment{CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR"})
+		require.NoError(t, err)
+		require.NoError(t, repo.BulkCreateSeatAssignments(t.Context(), []model.FlightSeatAssignment{
+			{FlightAssignmentID: assignment.ID, Seat: "3B", Code: "R7X6-5JZ3-03J1"},
+			{FlightAssignmentID: assignment.ID, Seat: "7C", Code: "YDDR-7J6A-0XGG"},
+		}))
+
+		voucher, err := repo.FindVoucher(t.Context(), "DHA6-VXJ6-5SNE")
+		require.NoError(t, err)
+		assert.Nil(t, voucher)
+
+		voucher, err = repo.FindVoucher(t.Context(), "R7X6-5JZ3-03J1")
+		require.NoError(t, err)
+		require.NotNil(t, voucher)
+		assert.Equal(t, "3B", voucher.Seat.Seat)
+		assert.Equal(t, "JT692", voucher.Assignment.FlightNumber)
+		assert.Equal(t, model.VoucherIssued, voucher.State())
+
+		redeem := func(seatID uint) error {
+			return repo.WithinTx(t.Context(), func(tx repository.FlightRepository) error {
+				return tx.RedeemVoucher(t.Context(), &model.VoucherRedemption{FlightSeatAssignmentID: seatID, Code: "R7X6-5JZ3-03J1", Station: "CGK"})
+			})
+		}
+		require.NoError(t, redeem(voucher.Seat.ID))
+		assert.ErrorIs(t, redeem(voucher.Seat.ID), repository.ErrConflict)
+
+		voucher, err = repo.FindVoucher(t.Context(), "R7X6-5JZ3-03J1")
+		require.NoError(t, err)
+		require.NotNil(t, voucher.Redemption)
+		assert.Equal(t, "CGK", voucher.Redemption.Station)
+		assert.Equal(t, model.VoucherRedeemed, voucher.State())
+
+		// revoked seats are still found, so they can be reported as revoked
+		require.NoError(t, repo.RevokeSeats(t.Context(), assignment.ID, []string{"7C"}, "ops", "crew change"))
+		voucher, err = repo.FindVoucher(t.Context(), "YDDR-7J6A-0XGG")
+		require.NoError(t, err)
+		require.NotNil(t, voucher)
+		assert.Equal(t, model.VoucherRevoked, voucher.State())
+	})
+}
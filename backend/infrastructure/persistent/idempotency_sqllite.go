@@ -0,0 +1,57 @@
+package persistent
+
+import (
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/repository"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) repository.IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error) {
+	var existing *model.IdempotencyRecord
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", now).Delete(&model.IdempotencyRecord{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+
+		existing = &model.IdempotencyRecord{}
+		return tx.Where("idempotency_key = ?", record.Key).First(existing).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	err := r.db.WithContext(ctx).Model(&model.IdempotencyRecord{}).
+		Where("idempotency_key = ?", key).
+		Updates(map[string]any{"status_code": status, "content_type": contentType, "body": body}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("idempotency_key = ?", key).Delete(&model.IdempotencyRecord{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
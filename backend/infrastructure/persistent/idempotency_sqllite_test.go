@@ -0,0 +1,46 @@
+package persistent
+
+import (
+	"bookcabin-voucher/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewIdempotencyRepository(db)
+		now := time.Now()
+		record := func() *model.IdempotencyRecord {
+			return &model.IdempotencyRecord{Key: "abc", RequestHash: "h1", ExpiresAt: now.Add(time.Hour)}
+		}
+
+		existing, err := repo.Reserve(t.Context(), record(), now)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		// a second request while the first is running sees an incomplete record
+		existing, err = repo.Reserve(t.Context(), record(), now)
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.False(t, existing.Completed())
+
+		require.NoError(t, repo.Complete(t.Context(), "abc", 201, "application/json", []byte(`{"ok":true}`)))
+		existing, err = repo.Reserve(t.Context(), record(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 201, existing.StatusCode)
+		assert.Equal(t, []byte(`{"ok":true}`), existing.Body)
+
+		// once expired the key can be claimed again
+		existing, err = repo.Reserve(t.Context(), record(), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		require.NoError(t, repo.Release(t.Context(), "abc"))
+		existing, err = repo.Reserve(t.Context(), record(), now)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bookcabin-voucher/internal/api/model"
+	"bookcabin-voucher/internal/service"
+	"bookcabin-voucher/internal/usecase"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strings"
+)
+
+// domainErrors maps the usecase and service errors to an HTTP status and error code.
+// The first match wins, so more specific errors come first.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{usecase.ErrInvalidCursor, http.StatusBadRequest, model.CodeInvalidCursor},
+	{usecase.ErrAircraftRequired, http.StatusBadRequest, model.CodeAircraftRequired},
+	{usecase.ErrAssignmentNotFound, http.StatusNotFound, model.CodeAssignmentNotFound},
+	{usecase.ErrSeatNotAssigned, http.StatusNotFound, model.CodeSeatNotAssigned},
+	{usecase.ErrVoucherNotFound, http.StatusNotFound, model.CodeVoucherNotFound},
+	{usecase.ErrVoucherRedeemed, http.StatusConflict, model.CodeVoucherRedeemed},
+	{usecase.ErrVoucherRevoked, http.StatusGone, model.CodeVoucherRevoked},
+	{usecase.ErrVoucherMismatch, http.StatusUnprocessableEntity, model.CodeVoucherMismatch},
+	{usecase.ErrTokenSigningDisabled, http.StatusUnprocessableEntity, model.CodeTokenSigningDisabled},
+	{usecase.ErrAssignmentExists, http.StatusConflict, model.CodeAssignmentExists},
+	{usecase.ErrAssignmentMismatch, http.StatusConflict, model.CodeAssignmentMismatch},
+	{usecase.ErrConcurrentChange, http.StatusConflict, model.CodeConcurrentChange},
+	{usecase.ErrInvalidSeats, http.StatusUnprocessableEntity, model.CodeInvalidSeats},
+	{usecase.ErrTooManyVouchers, http.StatusUnprocessableEntity, model.CodeTooManyVouchers},
+	{service.ErrUnknownAircraft, http.StatusUnprocessableEntity, model.CodeUnknownAircraft},
+	{service.ErrUnknownStrategy, http.StatusUnprocessableEntity, model.CodeUnknownStrategy},
+	{service.ErrNotEnoughSeats, http.StatusUnprocessableEntity, model.CodeNotEnoughSeats},
+}
+
+// respondError writes err with the status of its domain error, or 500 for anything else.
+// The values a usecase rejected are listed as details.
+func respondError(c *gin.Context, err error) {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			c.JSON(d.status, model.ErrorResponse{Error: err.Error(), Code: d.code, Details: rejectedFields(err)})
+			return
+		}
+	}
+	c.JSON(http.StatusInternalServerError, model.ErrorResponse{Error: err.Error(), Code: model.CodeInternal})
+}
+
+// respondBindError writes a 400 for a request that failed to bind, listing
+// each invalid field when the failure came from the validator.
+func respondBindError(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid input: " + err.Error(),
+			Code:  model.CodeInvalidRequest,
+		})
+		return
+	}
+
+	details := make([]model.FieldError, 0, len(invalid))
+	messages := make([]string, 0, len(invalid))
+	for _, fe := range invalid {
+		detail := model.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		}
+		details = append(details, detail)
+		messages = append(messages, detail.Field+" "+detail.Message)
+	}
+	c.JSON(http.StatusBadRequest, model.ErrorResponse{
+		Error:   "Invalid input: " + strings.Join(messages, "; "),
+		Code:    model.CodeValidationFailed,
+		Details: details,
+	})
+}
+
+func rejectedFields(err error) []model.FieldError {
+	var invalid *usecase.ValidationError
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	details := make([]model.FieldError, 0, len(invalid.Fields))
+	for _, f := range invalid.Fields {
+		details = append(details, model.FieldError{Field: f.Field, Rule: f.Rule, Value: f.Value, Message: f.Message})
+	}
+	return details
+}
+
+// fieldMessage explains a failed validation tag in plain words.
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "len":
+		return "must be exactly " + fe.Param() + " characters"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "datetime":
+		return "must be a date formatted DD-MM-YY"
+	case "alpha":
+		return "must contain only letters"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "uppercase":
+		return "must be uppercase"
+	case "flight_number":
+		return "must be an airline code followed by 1-4 digits, e.g. JT692"
+	case "aircraft_enum":
+		return "is not a supported aircraft"
+	case "seat_strategy":
+		return "is not a known seat strategy"
+	case "voucher_code":
+		return "is not a valid voucher code, e.g. R7X6-5JZ3-03J1"
+	default:
+		return fmt.Sprintf("failed the %s check", fe.Tag())
+	}
+}
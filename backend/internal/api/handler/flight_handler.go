@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"bookcabin-voucher/internal/api/model"
 	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/middleware"
 	serviceModel "bookcabin-voucher/internal/model"
 	"bookcabin-voucher/internal/usecase"
 	"github.com/gin-gonic/gin"
@@ -23,12 +23,10 @@ func (h *FlightHandler) CheckFlight(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[CheckFlight] Validation failed: %v", err)
 
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Error: "Invalid input: " + err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
-	exists := h.Usecase.CheckFlightExists(req)
+	exists := h.Usecase.CheckFlightExists(c.Request.Context(), req)
 	c.JSON(http.StatusOK, dto.CheckFlightResponse{Exists: exists})
 }
 
@@ -37,22 +35,245 @@ func (h *FlightHandler) Generate(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[Generate] Validation failed: %v", err)
 
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Error: "Invalid input: " + err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
-	assignment, err := h.Usecase.GenerateAndAssignSeats(req)
+	req.Meta = requestMeta(c)
+	assignment, err := h.Usecase.GenerateAndAssignSeats(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Error: err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, dto.GenerateResponse{
-		Success: true,
-		Seats:   splitSeats(assignment.SeatAssignments),
+		Success:  true,
+		Seats:    splitSeats(assignment.SeatAssignments),
+		Vouchers: toVoucherResponses(assignment.SeatAssignments),
 	})
 }
 
+func (h *FlightHandler) GetAssignment(c *gin.Context) {
+	var req dto.FlightPathRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		log.Printf("[GetAssignment] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	assignment, err := h.Usecase.GetAssignment(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAssignmentResponse(assignment))
+}
+
+func (h *FlightHandler) RevokeAssignment(c *gin.Context) {
+	var path dto.FlightPathRequest
+	var req dto.RevokeRequest
+	if err := bindRevoke(c, &path, &req); err != nil {
+		log.Printf("[RevokeAssignment] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	req.Meta = requestMeta(c)
+	err := h.Usecase.RevokeAssignment(c.Request.Context(), path, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FlightHandler) RevokeSeat(c *gin.Context) {
+	var path dto.SeatPathRequest
+	var req dto.RevokeRequest
+	if err := bindRevoke(c, &path, &req); err != nil {
+		log.Printf("[RevokeSeat] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	req.Meta = requestMeta(c)
+	assignment, err := h.Usecase.RevokeSeat(c.Request.Context(), path, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if assignment == nil {
+		// that was the last seat, so the assignment is gone as well
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, toAssignmentResponse(assignment))
+}
+
+func (h *FlightHandler) GetHistory(c *gin.Context) {
+	var req dto.FlightPathRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		log.Printf("[GetHistory] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	events, err := h.Usecase.GetHistory(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp := dto.HistoryResponse{
+		FlightNumber: req.FlightNumber,
+		Date:         req.Date,
+		Events:       make([]dto.AssignmentEventResponse, 0, len(events)),
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, dto.AssignmentEventResponse{
+			Type:      string(e.Type),
+			SeatsFrom: e.SeatsFrom,
+			SeatsTo:   e.SeatsTo,
+			Reason:    e.Reason,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			ClientIP:  e.ClientIP,
+			UserAgent: e.UserAgent,
+			At:        e.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// requestMeta collects the caller details recorded in the audit log.
+func requestMeta(c *gin.Context) dto.RequestMeta {
+	return dto.RequestMeta{
+		Actor:     c.GetHeader("X-Actor"),
+		RequestID: c.GetString(middleware.RequestIDKey),
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// bindRevoke binds the flight path and the revocation body of a DELETE request.
+func bindRevoke(c *gin.Context, path any, req *dto.RevokeRequest) error {
+	if err := c.ShouldBindUri(path); err != nil {
+		return err
+	}
+	return c.ShouldBindJSON(req)
+}
+
+func (h *FlightHandler) ListAssignments(c *gin.Context) {
+	var req dto.ListAssignmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Printf("[ListAssignments] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	assignments, nextCursor, err := h.Usecase.ListAssignments(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp := dto.ListAssignmentsResponse{
+		Assignments: make([]dto.AssignmentResponse, 0, len(assignments)),
+		NextCursor:  nextCursor,
+	}
+	for i := range assignments {
+		resp.Assignments = append(resp.Assignments, toAssignmentResponse(&assignments[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FlightHandler) ListAircraft(c *gin.Context) {
+	aircraft := h.Usecase.ListAircraft(c.Request.Context())
+
+	resp := dto.ListAircraftResponse{Aircraft: make([]dto.AircraftResponse, 0, len(aircraft))}
+	for _, a := range aircraft {
+		aliases := a.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		resp.Aircraft = append(resp.Aircraft, dto.AircraftResponse{
+			Type:            string(a.Type),
+			Aliases:         aliases,
+			Seats:           a.Seats,
+			DefaultVouchers: a.DefaultVouchers,
+			MaxVouchers:     a.MaxVouchers,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FlightHandler) GetSeatInventory(c *gin.Context) {
+	var req dto.SeatInventoryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		log.Printf("[GetSeatInventory] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Printf("[GetSeatInventory] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	inventory, err := h.Usecase.GetSeatInventory(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	seats := make([]dto.SeatStatusResponse, 0, len(inventory.Seats))
+	for _, s := range inventory.Seats {
+		seats = append(seats, dto.SeatStatusResponse{Seat: s.Seat, Status: string(s.Status)})
+	}
+	c.JSON(http.StatusOK, dto.SeatInventoryResponse{
+		FlightNumber: inventory.FlightNumber,
+		Date:         inventory.FlightDate,
+		Aircraft:     string(inventory.Aircraft),
+		Capacity:     inventory.Capacity,
+		Free:         inventory.Counts[serviceModel.SeatFree],
+		Issued:       inventory.Counts[serviceModel.SeatIssued],
+		Seats:        seats,
+	})
+}
+
+func toAssignmentResponse(a *serviceModel.FlightAssignment) dto.AssignmentResponse {
+	resp := dto.AssignmentResponse{
+		CrewName:     a.CrewName,
+		CrewID:       a.CrewID,
+		FlightNumber: a.FlightNumber,
+		Date:         a.FlightDate,
+		Aircraft:     string(a.AircraftType),
+		Seats:        make([]dto.SeatAssignmentResponse, 0, len(a.SeatAssignments)),
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.CreatedAt,
+	}
+	for _, s := range a.SeatAssignments {
+		resp.Seats = append(resp.Seats, dto.SeatAssignmentResponse{Seat: s.Seat, Code: s.Code, IssuedAt: s.CreatedAt})
+		if s.CreatedAt.After(resp.UpdatedAt) {
+			resp.UpdatedAt = s.CreatedAt
+		}
+	}
+	return resp
+}
+
+func toVoucherResponses(seats []serviceModel.FlightSeatAssignment) []dto.VoucherResponse {
+	vouchers := make([]dto.VoucherResponse, 0, len(seats))
+	for _, s := range seats {
+		vouchers = append(vouchers, dto.VoucherResponse{Seat: s.Seat, Code: s.Code, Token: s.Token})
+	}
+	return vouchers
+}
+
 func splitSeats(seats []serviceModel.FlightSeatAssignment) []string {
 	if len(seats) == 0 {
 		return []string{}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bookcabin-voucher/infrastructure/persistent"
+	apiModel "bookcabin-voucher/internal/api/model"
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/migration"
+	"bookcabin-voucher/internal/service"
+	"bookcabin-voucher/internal/usecase"
+	"bookcabin-voucher/internal/validation"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// setupStack wires the handler to the real usecase, repository and seat
+// allocator. The database is a SQLite file because every pooled connection to
+// :memory: would see its own empty database.
+func setupStack(t *testing.T) *gin.Engine {
+	db, err := persistent.Open(persistent.DriverSQLite, filepath.Join(t.TempDir(), "vouchers.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	_, err = migration.Up(t.Context(), db)
+	require.NoError(t, err)
+
+	seats, err := service.NewSeatAllocator(filepath.Join("..", "..", "..", "data", "layout.json"), rand.NewSource(1), nil)
+	require.NoError(t, err)
+	validation.RegisterValidators(seats)
+
+	repo := persistent.NewFlightRepository(db)
+	h := NewFlightHandler(usecase.NewFlightUsecase(repo, seats, nil))
+	vh := NewVoucherHandler(usecase.NewVoucherUsecase(repo, nil, nil))
+	r := gin.New()
+	r.POST("/api/generate", h.Generate)
+	r.GET("/api/flights/:flightNumber/:date/assignment", h.GetAssignment)
+	r.DELETE("/api/flights/:flightNumber/:date/assignment/seats/:seat", h.RevokeSeat)
+	r.POST("/api/vouchers/:code/redeem", vh.RedeemVoucher)
+	return r
+}
+
+// fireGenerate sends every body to /api/generate at once and returns the responses.
+func fireGenerate(r *gin.Engine, bodies []string) []*httptest.ResponseRecorder {
+	return firePost(r, "/api/generate", bodies)
+}
+
+// firePost sends every body to url at once and returns the responses.
+func firePost(r *gin.Engine, url string, bodies []string) []*httptest.ResponseRecorder {
+	responses := make([]*httptest.ResponseRecorder, len(bodies))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, body := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			responses[i] = httptest.NewRecorder()
+			<-start
+			r.ServeHTTP(responses[i], req)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return responses
+}
+
+func statusCounts(responses []*httptest.ResponseRecorder) map[int]int {
+	counts := make(map[int]int)
+	for _, resp := range responses {
+		counts[resp.Code]++
+	}
+	return counts
+}
+
+func TestGenerate_ConcurrentRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupStack(t)
+
+	const flights, perFlight = 10, 30
+	flightNumber := func(f int) string { return fmt.Sprintf("JT%d", 100+f) }
+	generate := func(f int, seats []string) string {
+		body, _ := json.Marshal(map[string]any{
+			"name": "Sarah", "id": "98123", "flightNumber": flightNumber(f), "date": "12-07-25",
+			"aircraft": "Airbus 320", "seats": seats,
+		})
+		return string(body)
+	}
+
+	// every flight is generated once however many requests race for it
+	var bodies []string
+	for f := 0; f < flights; f++ {
+		for i := 0; i < perFlight; i++ {
+			bodies = append(bodies, generate(f, nil))
+		}
+	}
+	responses := fireGenerate(r, bodies)
+	assert.Equal(t, map[int]int{http.StatusOK: flights, http.StatusConflict: flights * (perFlight - 1)}, statusCounts(responses))
+	for _, resp := range responses {
+		if resp.Code == http.StatusConflict {
+			var body apiModel.ErrorResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			assert.Contains(t, []string{apiModel.CodeAssignmentExists, apiModel.CodeConcurrentChange}, body.Code)
+		}
+	}
+
+	// racing re-rolls of the same seat: one replaces it, the rest find it gone
+	held := make(map[int][]string, flights)
+	bodies = bodies[:0]
+	for f := 0; f < flights; f++ {
+		held[f] = assignedSeats(t, r, flightNumber(f))
+		require.Len(t, held[f], 3)
+		for i := 0; i < perFlight; i++ {
+			bodies = append(bodies, generate(f, held[f][:1]))
+		}
+	}
+	responses = fireGenerate(r, bodies)
+	assert.Equal(t, map[int]int{http.StatusOK: flights, http.StatusUnprocessableEntity: flights * (perFlight - 1)}, statusCounts(responses))
+
+	for f := 0; f < flights; f++ {
+		seats := assignedSeats(t, r, flightNumber(f))
+		assert.Len(t, seats, 3)
+		assert.NotContains(t, seats, held[f][0])
+		unique := make(map[string]bool, len(seats))
+		for _, seat := range seats {
+			unique[seat] = true
+		}
+		assert.Len(t, unique, len(seats), "duplicate seats on %s: %v", flightNumber(f), seats)
+	}
+}
+
+func TestRedeemVoucher_ConcurrentRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupStack(t)
+
+	responses := fireGenerate(r, []string{`{"name":"Sarah","id":"98123","flightNumber":"JT692","date":"12-07-25","aircraft":"ATR"}`})
+	require.Equal(t, http.StatusOK, responses[0].Code)
+	var generated dto.GenerateResponse
+	require.NoError(t, json.Unmarshal(responses[0].Body.Bytes(), &generated))
+	voucher := generated.Vouchers[0]
+
+	// one gate wins, every other attempt sees the voucher already used
+	body := fmt.Sprintf(`{"flightNumber":"JT692","date":"12-07-25","seat":%q,"station":"CGK"}`, voucher.Seat)
+	bodies := make([]string, 50)
+	for i := range bodies {
+		bodies[i] = body
+	}
+	responses = firePost(r, "/api/vouchers/"+voucher.Code+"/redeem", bodies)
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: len(bodies) - 1}, statusCounts(responses))
+}
+
+func TestRevokeLastSeat_AllowsRegeneration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupStack(t)
+
+	generate := `{"name":"Sarah","id":"98123","flightNumber":"JT692","date":"12-07-25","aircraft":"ATR","count":2}`
+	require.Equal(t, http.StatusOK, fireGenerate(r, []string{generate})[0].Code)
+
+	revoke := func(seat string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/flights/JT692/12-07-25/assignment/seats/"+seat,
+			bytes.NewBufferString(`{"actor":"ops","reason":"crew change"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	seats := assignedSeats(t, r, "JT692")
+	require.Len(t, seats, 2)
+	assert.Equal(t, http.StatusOK, revoke(seats[0]))
+	// the last seat takes the assignment with it
+	assert.Equal(t, http.StatusNoContent, revoke(seats[1]))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/12-07-25/assignment", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	require.Equal(t, http.StatusOK, fireGenerate(r, []string{generate})[0].Code)
+	assert.Len(t, assignedSeats(t, r, "JT692"), 2)
+}
+
+func assignedSeats(t *testing.T, r *gin.Engine, flightNumber string) []string {
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/"+flightNumber+"/12-07-25/assignment", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var assignment dto.AssignmentResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &assignment))
+	seats := make([]string, 0, len(assignment.Seats))
+	for _, seat := range assignment.Seats {
+		seats = append(seats, seat.Seat)
+	}
+	return seats
+}
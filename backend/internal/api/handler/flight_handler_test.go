@@ -4,6 +4,8 @@ import (
 	apiModel "bookcabin-voucher/internal/api/model"
 	"bookcabin-voucher/internal/dto"
 	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
+	"bookcabin-voucher/internal/usecase"
 	"bookcabin-voucher/internal/validation"
 	mockUc "bookcabin-voucher/mocks/usecase"
 	"bytes"
@@ -15,20 +17,27 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
+var testAircraft = service.NewAircraftCatalog(map[model.AircraftType]model.AircraftLayout{
+	"ATR":            {},
+	"Airbus 320":     {Aliases: []string{"A320"}},
+	"Boeing 737 Max": {},
+})
+
 func TestCheckFlightHandler_Exists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	validation.RegisterValidators()
+	validation.RegisterValidators(testAircraft)
 
 	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
 	h := NewFlightHandler(mockUsecase)
 	r := gin.Default()
 	r.POST("/api/check", h.CheckFlight)
 
-	mockUsecase.EXPECT().CheckFlightExists(dto.CheckFlightRequest{
+	mockUsecase.EXPECT().CheckFlightExists(gomock.Any(), dto.CheckFlightRequest{
 		FlightNumber: "JT692",
 		Date:         "26-07-25",
 	}).Return(true)
@@ -48,7 +57,7 @@ func TestGenerateFlightHandler_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	validation.RegisterValidators()
+	validation.RegisterValidators(testAircraft)
 
 	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
 	h := NewFlightHandler(mockUsecase)
@@ -64,19 +73,26 @@ func TestGenerateFlightHandler_Success(t *testing.T) {
 	}
 
 	assignment := &model.FlightAssignment{
-		CrewName:        "ApArki",
-		CrewID:          "98123",
-		FlightNumber:    "JT692",
-		FlightDate:      "26-07-25",
-		AircraftType:    "Airbus 320",
-		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3A"}, {Seat: "5C"}, {Seat: "8F"}},
+		CrewName:     "ApArki",
+		CrewID:       "98123",
+		FlightNumber: "JT692",
+		FlightDate:   "26-07-25",
+		AircraftType: "Airbus 320",
+		SeatAssignments: []model.FlightSeatAssignment{
+			{Seat: "3A", Code: "R7X6-5JZ3-03J1"}, {Seat: "5C", Code: "YDDR-7J6A-0XGG"}, {Seat: "8F", Code: "DHA6-VXJ6-5SNE"},
+		},
 	}
 
-	mockUsecase.EXPECT().GenerateAndAssignSeats(reqData).Return(assignment, nil)
+	// the handler records who sent the request for the audit log
+	expected := reqData
+	expected.Meta = dto.RequestMeta{Actor: "dispatcher", ClientIP: "192.0.2.1", UserAgent: "voucher-test"}
+	mockUsecase.EXPECT().GenerateAndAssignSeats(gomock.Any(), expected).Return(assignment, nil)
 
 	body, _ := json.Marshal(reqData)
 	req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Actor", "dispatcher")
+	req.Header.Set("User-Agent", "voucher-test")
 	resp := httptest.NewRecorder()
 
 	r.ServeHTTP(resp, req)
@@ -85,14 +101,20 @@ func TestGenerateFlightHandler_Success(t *testing.T) {
 	var bodyResp dto.GenerateResponse
 	err := json.Unmarshal(resp.Body.Bytes(), &bodyResp)
 	assert.NoError(t, err)
-	assert.Equal(t, bodyResp, dto.GenerateResponse{Success: true, Seats: []string{"3A", "5C", "8F"}})
+	assert.Equal(t, dto.GenerateResponse{
+		Success: true,
+		Seats:   []string{"3A", "5C", "8F"},
+		Vouchers: []dto.VoucherResponse{
+			{Seat: "3A", Code: "R7X6-5JZ3-03J1"}, {Seat: "5C", Code: "YDDR-7J6A-0XGG"}, {Seat: "8F", Code: "DHA6-VXJ6-5SNE"},
+		},
+	}, bodyResp)
 }
 
 func TestGenerateFlightHandler_ValidationAircraftFail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	validation.RegisterValidators()
+	validation.RegisterValidators(testAircraft)
 
 	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
 	h := NewFlightHandler(mockUsecase)
@@ -126,7 +148,7 @@ func TestGenerateFlightHandler_ValidationFlightNumberFail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	validation.RegisterValidators()
+	validation.RegisterValidators(testAircraft)
 
 	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
 	h := NewFlightHandler(mockUsecase)
@@ -173,7 +195,9 @@ func TestGenerateFlightHandler_UsecaseError(t *testing.T) {
 		Aircraft:     "Airbus 320",
 	}
 
-	mockUsecase.EXPECT().GenerateAndAssignSeats(reqData).
+	expected := reqData
+	expected.Meta = dto.RequestMeta{ClientIP: "192.0.2.1"}
+	mockUsecase.EXPECT().GenerateAndAssignSeats(gomock.Any(), expected).
 		Return(nil, fmt.Errorf("assignment for this flight and date already exists"))
 
 	body, _ := json.Marshal(reqData)
@@ -189,3 +213,488 @@ func TestGenerateFlightHandler_UsecaseError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, bodyResp.Error, "assignment for this flight and date already exists")
 }
+
+func TestListAircraftHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/aircraft", h.ListAircraft)
+
+	mockUsecase.EXPECT().ListAircraft(gomock.Any()).Return([]model.AircraftInfo{
+		{Type: "ATR", Seats: 70},
+		{Type: "Airbus 320", Aliases: []string{"A320"}, Seats: 180, DefaultVouchers: 3, MaxVouchers: 6},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/aircraft", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"aircraft": [
+		{"type": "ATR", "aliases": [], "seats": 70, "defaultVouchers": 0, "maxVouchers": 0},
+		{"type": "Airbus 320", "aliases": ["A320"], "seats": 180, "defaultVouchers": 3, "maxVouchers": 6}
+	]}`, resp.Body.String())
+}
+
+func TestGetSeatInventoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/inventory", h.GetSeatInventory)
+
+	mockUsecase.EXPECT().GetSeatInventory(gomock.Any(), dto.SeatInventoryRequest{
+		FlightNumber: "JT692", Date: "26-07-25", Aircraft: "A320",
+	}).Return(&model.SeatInventory{
+		FlightNumber: "JT692",
+		FlightDate:   "26-07-25",
+		Aircraft:     "Airbus 320",
+		Capacity:     174,
+		Counts:       map[model.SeatStatus]int{model.SeatFree: 171, model.SeatIssued: 3},
+		Seats: []model.FlightSeatInventory{
+			{Seat: "3A", Status: model.SeatIssued},
+			{Seat: "5C", Status: model.SeatIssued},
+			{Seat: "8F", Status: model.SeatIssued},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/inventory?aircraft=A320", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"flightNumber": "JT692", "date": "26-07-25", "aircraft": "Airbus 320",
+		"capacity": 174, "free": 171, "issued": 3,
+		"seats": [
+			{"seat": "3A", "status": "voucher-issued"},
+			{"seat": "5C", "status": "voucher-issued"},
+			{"seat": "8F", "status": "voucher-issued"}
+		]
+	}`, resp.Body.String())
+}
+
+func TestGetSeatInventoryHandler_InvalidFlightNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/inventory", h.GetSeatInventory)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/ID12345/26-07-25/inventory", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestGenerateFlightHandler_ValidationCountFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.POST("/api/generate", h.Generate)
+
+	reqBody := `{
+		"name": "ApArki",
+		"id": "122511",
+		"flightNumber": "JT692",
+		"date": "12-07-25",
+		"aircraft": "Airbus 320",
+		"count": 99
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestGetAssignmentHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/assignment", h.GetAssignment)
+
+	created := time.Date(2025, 7, 20, 9, 0, 0, 0, time.UTC)
+	rerolled := created.Add(time.Hour)
+	mockUsecase.EXPECT().GetAssignment(gomock.Any(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}).
+		Return(&model.FlightAssignment{
+			CrewName:     "ApArki",
+			CrewID:       "98123",
+			FlightNumber: "JT692",
+			FlightDate:   "26-07-25",
+			AircraftType: "Airbus 320",
+			SeatAssignments: []model.FlightSeatAssignment{
+				{Seat: "3A", CreatedAt: created},
+				{Seat: "5C", CreatedAt: rerolled},
+			},
+			CreatedAt: created,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/assignment", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"name": "ApArki", "id": "98123", "flightNumber": "JT692", "date": "26-07-25", "aircraft": "Airbus 320",
+		"seats": [
+			{"seat": "3A", "issuedAt": "2025-07-20T09:00:00Z"},
+			{"seat": "5C", "issuedAt": "2025-07-20T10:00:00Z"}
+		],
+		"createdAt": "2025-07-20T09:00:00Z",
+		"updatedAt": "2025-07-20T10:00:00Z"
+	}`, resp.Body.String())
+}
+
+func TestGetAssignmentHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/assignment", h.GetAssignment)
+
+	mockUsecase.EXPECT().GetAssignment(gomock.Any(), gomock.Any()).Return(nil, usecase.ErrAssignmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/assignment", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestListAssignmentsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/assignments", h.ListAssignments)
+
+	mockUsecase.EXPECT().ListAssignments(gomock.Any(), dto.ListAssignmentsRequest{
+		CrewID: "270123", Sort: "-flightDate", Limit: 1,
+	}).Return([]model.FlightAssignment{{ID: 1, CrewID: "270123", FlightNumber: "JT692"}}, "next", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/assignments?crewId=270123&sort=-flightDate&limit=1", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body dto.ListAssignmentsResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "next", body.NextCursor)
+	assert.Len(t, body.Assignments, 1)
+	assert.Equal(t, "JT692", body.Assignments[0].FlightNumber)
+}
+
+func TestListAssignmentsHandler_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/assignments", h.ListAssignments)
+
+	for _, query := range []string{"sort=crewName", "limit=500", "from=2025-07-01"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/assignments?"+query, nil)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+
+	mockUsecase.EXPECT().ListAssignments(gomock.Any(), gomock.Any()).Return(nil, "", usecase.ErrInvalidCursor)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/assignments?cursor=bogus", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRevokeAssignmentHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.DELETE("/api/flights/:flightNumber/:date/assignment", h.RevokeAssignment)
+
+	revoke := dto.RevokeRequest{Actor: "ops", Reason: "flight cancelled"}
+	body, _ := json.Marshal(revoke)
+
+	revoke.Meta = dto.RequestMeta{ClientIP: "192.0.2.1"}
+	mockUsecase.EXPECT().RevokeAssignment(gomock.Any(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, revoke).Return(nil)
+	mockUsecase.EXPECT().RevokeAssignment(gomock.Any(), gomock.Any(), revoke).Return(usecase.ErrAssignmentNotFound)
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/flights/JT692/26-07-25/assignment", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, want, resp.Code)
+	}
+
+	// a reason is mandatory
+	req := httptest.NewRequest(http.MethodDelete, "/api/flights/JT692/26-07-25/assignment", bytes.NewBufferString(`{"actor":"ops"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestRevokeSeatHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.DELETE("/api/flights/:flightNumber/:date/assignment/seats/:seat", h.RevokeSeat)
+
+	revoke := dto.RevokeRequest{Actor: "ops", Reason: "crew change"}
+	body, _ := json.Marshal(revoke)
+	revoke.Meta = dto.RequestMeta{ClientIP: "192.0.2.1"}
+	seat := dto.SeatPathRequest{FlightPathRequest: dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, Seat: "3B"}
+	mockUsecase.EXPECT().RevokeSeat(gomock.Any(), seat, revoke).Return(&model.FlightAssignment{
+		FlightNumber: "JT692", SeatAssignments: []model.FlightSeatAssignment{{Seat: "7C"}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/flights/JT692/26-07-25/assignment/seats/3B", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var got dto.AssignmentResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	assert.Len(t, got.Seats, 1)
+	assert.Equal(t, "7C", got.Seats[0].Seat)
+
+	mockUsecase.EXPECT().RevokeSeat(gomock.Any(), gomock.Any(), revoke).Return(nil, usecase.ErrSeatNotAssigned)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/flights/JT692/26-07-25/assignment/seats/9A", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestGetHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/history", h.GetHistory)
+
+	flight := dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}
+	mockUsecase.EXPECT().GetHistory(gomock.Any(), flight).Return([]model.FlightAssignmentEvent{
+		{Type: model.EventCreated, SeatsTo: []string{"3B", "7C", "14D"}, Actor: "270123"},
+		{Type: model.EventReRolled, SeatsFrom: []string{"14D"}, SeatsTo: []string{"12A"}, Actor: "270123"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/history", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var got dto.HistoryResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	assert.Len(t, got.Events, 2)
+	assert.Equal(t, "re-rolled", got.Events[1].Type)
+	assert.Equal(t, []string{"14D"}, got.Events[1].SeatsFrom)
+	assert.Equal(t, []string{"12A"}, got.Events[1].SeatsTo)
+
+	mockUsecase.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(nil, usecase.ErrAssignmentNotFound)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/flights/JT692/27-07-25/history", nil)
+	resp = httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestGenerateFlightHandler_DomainErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.POST("/api/generate", h.Generate)
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{usecase.ErrAssignmentExists, http.StatusConflict, apiModel.CodeAssignmentExists},
+		{fmt.Errorf("failed to generate seats: %w", service.ErrNotEnoughSeats), http.StatusUnprocessableEntity, apiModel.CodeNotEnoughSeats},
+		{fmt.Errorf("%w: ATR allows at most 4", usecase.ErrTooManyVouchers), http.StatusUnprocessableEntity, apiModel.CodeTooManyVouchers},
+		{service.ErrUnknownAircraft, http.StatusUnprocessableEntity, apiModel.CodeUnknownAircraft},
+		{fmt.Errorf("failed to commit transaction"), http.StatusInternalServerError, apiModel.CodeInternal},
+	}
+	body, _ := json.Marshal(dto.GenerateRequest{
+		CrewName: "Sarah", CrewID: "98123", FlightNumber: "JT692", Date: "12-07-25", Aircraft: "Airbus 320",
+	})
+	for _, tt := range tests {
+		mockUsecase.EXPECT().GenerateAndAssignSeats(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, tt.status, resp.Code, tt.err.Error())
+		var got apiModel.ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+		assert.Equal(t, tt.code, got.Code)
+		assert.Equal(t, tt.err.Error(), got.Error)
+	}
+}
+
+func TestGenerateFlightHandler_ValidationDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.POST("/api/generate", h.Generate)
+
+	reqBody := `{"name": "ApArki", "flightNumber": "ID12345", "date": "12-07-25", "aircraft": "Airbus 320", "count": 50}`
+	req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var body apiModel.ErrorResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, apiModel.CodeValidationFailed, body.Code)
+	assert.Equal(t, []apiModel.FieldError{
+		{Field: "id", Rule: "required", Message: "is required"},
+		{Field: "flightNumber", Rule: "flight_number", Message: "must be an airline code followed by 1-4 digits, e.g. JT692"},
+		{Field: "count", Rule: "max", Param: "20", Message: "must be at most 20"},
+	}, body.Details)
+
+	// malformed JSON has no field details
+	req = httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBufferString(`{"name":`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, apiModel.CodeInvalidRequest, body.Code)
+}
+
+func TestGenerateFlightHandler_InvalidSeatsDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockFlightUsecase(ctrl)
+	h := NewFlightHandler(mockUsecase)
+	r := gin.Default()
+	r.POST("/api/generate", h.Generate)
+
+	mockUsecase.EXPECT().GenerateAndAssignSeats(gomock.Any(), gomock.Any()).Return(nil, &usecase.ValidationError{
+		Err: usecase.ErrInvalidSeats,
+		Fields: []usecase.FieldError{
+			{Field: "seats[1]", Rule: "not_assigned", Value: "9A", Message: "seat 9A is not part of this assignment"},
+		},
+	})
+
+	body, _ := json.Marshal(dto.GenerateRequest{
+		CrewName: "Sarah", CrewID: "98123", FlightNumber: "JT692", Date: "12-07-25", Aircraft: "Airbus 320",
+		SeatsToChange: []string{"3B", "9A"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var got apiModel.ErrorResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	assert.Equal(t, apiModel.CodeInvalidSeats, got.Code)
+	assert.Equal(t, "invalid seats to change: seat 9A is not part of this assignment", got.Error)
+	assert.Equal(t, []apiModel.FieldError{
+		{Field: "seats[1]", Rule: "not_assigned", Value: "9A", Message: "seat 9A is not part of this assignment"},
+	}, got.Details)
+}
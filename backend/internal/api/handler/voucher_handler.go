@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bookcabin-voucher/internal/dto"
+	serviceModel "bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
+	"bookcabin-voucher/internal/usecase"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+type VoucherHandler struct {
+	Usecase usecase.VoucherUsecase
+}
+
+func NewVoucherHandler(u usecase.VoucherUsecase) *VoucherHandler {
+	return &VoucherHandler{Usecase: u}
+}
+
+func (h *VoucherHandler) GetVoucherPDF(c *gin.Context) {
+	var req dto.FlightPathRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		log.Printf("[GetVoucherPDF] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	// rendered into memory first so a failure still gets a JSON error response
+	var pdf bytes.Buffer
+	if err := h.Usecase.RenderVouchers(c.Request.Context(), req, &pdf); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s_%s_vouchers.pdf"`, req.FlightNumber, req.Date))
+	c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+func (h *VoucherHandler) GetSeatQR(c *gin.Context) {
+	var path dto.SeatPathRequest
+	if err := c.ShouldBindUri(&path); err != nil {
+		log.Printf("[GetSeatQR] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+	var req dto.SeatQRRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Printf("[GetSeatQR] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	var img bytes.Buffer
+	if err := h.Usecase.RenderSeatQR(c.Request.Context(), path, req, &img); err != nil {
+		respondError(c, err)
+		return
+	}
+	contentType := "image/png"
+	if req.Format == service.QRFormatSVG {
+		contentType = "image/svg+xml"
+	}
+	// a re-roll gives the seat a new voucher, so never serve a stale code
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, img.Bytes())
+}
+
+func (h *VoucherHandler) GetVoucher(c *gin.Context) {
+	var req dto.VoucherPathRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		log.Printf("[GetVoucher] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	voucher, err := h.Usecase.GetVoucher(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toVoucherStatusResponse(voucher))
+}
+
+func (h *VoucherHandler) RedeemVoucher(c *gin.Context) {
+	var path dto.VoucherPathRequest
+	if err := c.ShouldBindUri(&path); err != nil {
+		log.Printf("[RedeemVoucher] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+	var req dto.RedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[RedeemVoucher] Validation failed: %v", err)
+
+		respondBindError(c, err)
+		return
+	}
+
+	req.Meta = requestMeta(c)
+	if req.Actor == "" {
+		req.Actor = req.Meta.Actor
+	}
+	voucher, err := h.Usecase.RedeemVoucher(c.Request.Context(), path, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toVoucherStatusResponse(voucher))
+}
+
+// GetVoucherKeys serves the public keys of voucher tokens as a JSON Web Key Set.
+func (h *VoucherHandler) GetVoucherKeys(c *gin.Context) {
+	keys := h.Usecase.VoucherKeys()
+	resp := dto.VoucherKeySetResponse{Keys: make([]dto.VoucherKeyResponse, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, dto.VoucherKeyResponse{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			Kid: key.ID,
+			Alg: "EdDSA",
+			Use: "sig",
+			X:   base64.RawURLEncoding.EncodeToString(key.PublicKey),
+		})
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, resp)
+}
+
+func toVoucherStatusResponse(v *serviceModel.IssuedVoucher) dto.VoucherStatusResponse {
+	resp := dto.VoucherStatusResponse{
+		Code:         v.Seat.Code,
+		Status:       string(v.State()),
+		CrewName:     v.Assignment.CrewName,
+		CrewID:       v.Assignment.CrewID,
+		FlightNumber: v.Assignment.FlightNumber,
+		Date:         v.Assignment.FlightDate,
+		Aircraft:     string(v.Assignment.AircraftType),
+		Seat:         v.Seat.Seat,
+		IssuedAt:     v.Seat.CreatedAt,
+	}
+	if v.Seat.DeletedAt.Valid {
+		resp.RevokedAt = &v.Seat.DeletedAt.Time
+	} else if v.Assignment.DeletedAt.Valid {
+		resp.RevokedAt = &v.Assignment.DeletedAt.Time
+	}
+	if r := v.Redemption; r != nil {
+		resp.RedeemedAt = &r.RedeemedAt
+		resp.Station = r.Station
+		resp.RedeemedBy = r.RedeemedBy
+	}
+	return resp
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	apiModel "bookcabin-voucher/internal/api/model"
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
+	"bookcabin-voucher/internal/usecase"
+	"bookcabin-voucher/internal/validation"
+	mockUc "bookcabin-voucher/mocks/usecase"
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetVoucherPDFHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+	h := NewVoucherHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/voucher.pdf", h.GetVoucherPDF)
+
+	mockUsecase.EXPECT().RenderVouchers(gomock.Any(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, gomock.Any()).
+		DoAndReturn(func(_ any, _ dto.FlightPathRequest, w io.Writer) error {
+			_, err := w.Write([]byte("%PDF-1.3"))
+			return err
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/voucher.pdf", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="JT692_26-07-25_vouchers.pdf"`, resp.Header().Get("Content-Disposition"))
+	assert.Equal(t, "%PDF-1.3", resp.Body.String())
+}
+
+func TestGetVoucherPDFHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+	h := NewVoucherHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/voucher.pdf", h.GetVoucherPDF)
+
+	mockUsecase.EXPECT().RenderVouchers(gomock.Any(), gomock.Any(), gomock.Any()).Return(usecase.ErrAssignmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/voucher.pdf", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Header().Get("Content-Type"), "application/json")
+}
+
+func TestRedeemVoucherHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+	h := NewVoucherHandler(mockUsecase)
+	r := gin.Default()
+	r.POST("/api/vouchers/:code/redeem", h.RedeemVoucher)
+
+	issued := time.Date(2025, 7, 20, 8, 30, 0, 0, time.UTC)
+	redeemed := time.Date(2025, 7, 26, 6, 0, 0, 0, time.UTC)
+	mockUsecase.EXPECT().RedeemVoucher(gomock.Any(), dto.VoucherPathRequest{Code: "R7X6-5JZ3-03J1"}, gomock.Any()).
+		DoAndReturn(func(_ any, _ dto.VoucherPathRequest, req dto.RedeemRequest) (*model.IssuedVoucher, error) {
+			assert.Equal(t, "CGK", req.Station)
+			// the actor falls back to the X-Actor header
+			assert.Equal(t, "gate-7", req.Actor)
+			return &model.IssuedVoucher{
+				Seat:       model.FlightSeatAssignment{Seat: "3B", Code: "R7X6-5JZ3-03J1", CreatedAt: issued},
+				Assignment: model.FlightAssignment{CrewName: "ApArki", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", AircraftType: "ATR"},
+				Redemption: &model.VoucherRedemption{Station: "CGK", RedeemedBy: "gate-7", RedeemedAt: redeemed},
+			}, nil
+		})
+
+	body := `{"flightNumber":"JT692","date":"26-07-25","seat":"3B","station":"CGK"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/vouchers/R7X6-5JZ3-03J1/redeem", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Actor", "gate-7")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"code": "R7X6-5JZ3-03J1", "status": "redeemed",
+		"name": "ApArki", "id": "98123", "flightNumber": "JT692", "date": "26-07-25", "aircraft": "ATR", "seat": "3B",
+		"issuedAt": "2025-07-20T08:30:00Z", "redeemedAt": "2025-07-26T06:00:00Z", "station": "CGK", "redeemedBy": "gate-7"
+	}`, resp.Body.String())
+}
+
+func TestRedeemVoucherHandler_Errors(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		status int
+		code   string
+	}{
+		"mismatch":         {usecase.ErrVoucherMismatch, http.StatusUnprocessableEntity, apiModel.CodeVoucherMismatch},
+		"already redeemed": {usecase.ErrVoucherRedeemed, http.StatusConflict, apiModel.CodeVoucherRedeemed},
+		"revoked":          {usecase.ErrVoucherRevoked, http.StatusGone, apiModel.CodeVoucherRevoked},
+		"not found":        {usecase.ErrVoucherNotFound, http.StatusNotFound, apiModel.CodeVoucherNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			validation.RegisterValidators(testAircraft)
+
+			mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+			h := NewVoucherHandler(mockUsecase)
+			r := gin.Default()
+			r.POST("/api/vouchers/:code/redeem", h.RedeemVoucher)
+
+			mockUsecase.EXPECT().RedeemVoucher(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			body := `{"flightNumber":"JT692","date":"26-07-25","seat":"3B","station":"CGK"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/vouchers/R7X6-5JZ3-03J1/redeem", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+			var errResp apiModel.ErrorResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+			assert.Equal(t, tt.code, errResp.Code)
+		})
+	}
+}
+
+func TestGetVoucherHandler_InvalidCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	h := NewVoucherHandler(mockUc.NewMockVoucherUsecase(ctrl))
+	r := gin.Default()
+	r.GET("/api/vouchers/:code", h.GetVoucher)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/vouchers/R7X6-5JZ3-03J2", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "is not a valid voucher code")
+}
+
+func TestGetVoucherKeysHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	seed, _, err := service.NewVoucherSigningKey()
+	require.NoError(t, err)
+	signer, err := service.NewVoucherSigner("", map[string]string{"2025-07": seed}, nil, time.Hour)
+	require.NoError(t, err)
+
+	mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+	h := NewVoucherHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/.well-known/jwks.json", h.GetVoucherKeys)
+
+	mockUsecase.EXPECT().VoucherKeys().Return(signer.PublicKeys())
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body dto.VoucherKeySetResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Len(t, body.Keys, 1)
+	assert.Equal(t, "EdDSA", body.Keys[0].Alg)
+
+	// what third parties download verifies the tokens the API hands out
+	keys, err := service.ParseVoucherKeySet(resp.Body.Bytes())
+	require.NoError(t, err)
+	token, err := signer.Sign(model.Voucher{FlightNumber: "JT692", FlightDate: time.Now().Format("02-01-06"), Seat: "3B"})
+	require.NoError(t, err)
+	_, err = service.ParseVoucherToken(token, keys)
+	assert.NoError(t, err)
+}
+
+func TestGetSeatQRHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validation.RegisterValidators(testAircraft)
+
+	mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+	h := NewVoucherHandler(mockUsecase)
+	r := gin.Default()
+	r.GET("/api/flights/:flightNumber/:date/assignment/seats/:seat/qr", h.GetSeatQR)
+
+	mockUsecase.EXPECT().RenderSeatQR(gomock.Any(),
+		dto.SeatPathRequest{FlightPathRequest: dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, Seat: "3B"},
+		dto.SeatQRRequest{Format: "svg", Payload: "bcbp", From: "CGK", To: "DPS"},
+		gomock.Any(),
+	).DoAndReturn(func(_ any, _ dto.SeatPathRequest, _ dto.SeatQRRequest, w io.Writer) error {
+		_, err := w.Write([]byte("<svg></svg>"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/assignment/seats/3B/qr?format=svg&payload=bcbp&from=CGK&to=DPS", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	assert.Equal(t, "<svg></svg>", resp.Body.String())
+}
+
+func TestGetSeatQRHandler_Errors(t *testing.T) {
+	validation.RegisterValidators(testAircraft)
+
+	tests := map[string]struct {
+		query  string
+		err    error
+		status int
+		code   string
+	}{
+		"unknown format":  {query: "?format=gif", status: http.StatusBadRequest, code: apiModel.CodeValidationFailed},
+		"unknown payload": {query: "?payload=pdf417", status: http.StatusBadRequest, code: apiModel.CodeValidationFailed},
+		"bad airport":     {query: "?payload=bcbp&from=cgk", status: http.StatusBadRequest, code: apiModel.CodeValidationFailed},
+		"too small":       {query: "?size=8", status: http.StatusBadRequest, code: apiModel.CodeValidationFailed},
+		"seat not held":   {err: usecase.ErrSeatNotAssigned, status: http.StatusNotFound, code: apiModel.CodeSeatNotAssigned},
+		"token unsigned":  {query: "?payload=token", err: usecase.ErrTokenSigningDisabled, status: http.StatusUnprocessableEntity, code: apiModel.CodeTokenSigningDisabled},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := mockUc.NewMockVoucherUsecase(ctrl)
+			h := NewVoucherHandler(mockUsecase)
+			r := gin.Default()
+			r.GET("/api/flights/:flightNumber/:date/assignment/seats/:seat/qr", h.GetSeatQR)
+
+			if tt.err != nil {
+				mockUsecase.EXPECT().RenderSeatQR(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/flights/JT692/26-07-25/assignment/seats/3B/qr"+tt.query, nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+			var body apiModel.ErrorResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			assert.Equal(t, tt.code, body.Code)
+		})
+	}
+}
@@ -1,5 +1,42 @@
 package model
 
+// Machine-readable error codes returned in ErrorResponse.Code.
+const (
+	CodeInvalidRequest        = "invalid_request"   // body or query could not be parsed
+	CodeValidationFailed      = "validation_failed" // see Details for the offending fields
+	CodeInvalidCursor         = "invalid_cursor"
+	CodeAircraftRequired      = "aircraft_required"
+	CodeAssignmentNotFound    = "assignment_not_found"
+	CodeSeatNotAssigned       = "seat_not_assigned"
+	CodeAssignmentExists      = "assignment_exists"
+	CodeAssignmentMismatch    = "assignment_mismatch"
+	CodeConcurrentChange      = "concurrent_change" // lost a race with another request for the flight, safe to retry
+	CodeInvalidSeats          = "invalid_seats"
+	CodeVoucherNotFound       = "voucher_not_found"
+	CodeVoucherMismatch       = "voucher_mismatch"
+	CodeVoucherRedeemed       = "voucher_redeemed"
+	CodeVoucherRevoked        = "voucher_revoked"
+	CodeTokenSigningDisabled  = "token_signing_disabled"
+	CodeUnknownAircraft       = "unknown_aircraft"
+	CodeUnknownStrategy       = "unknown_strategy"
+	CodeNotEnoughSeats        = "not_enough_seats"
+	CodeTooManyVouchers       = "too_many_vouchers"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"  // same key, different request
+	CodeIdempotencyInProgress = "idempotency_in_progress" // the first request with the key has not finished
+	CodeInternal              = "internal_error"
+)
+
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string       `json:"error"`
+	Code    string       `json:"code,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError describes one request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`           // name as sent by the client, e.g. "flightNumber"
+	Rule    string `json:"rule"`            // validation tag that failed, e.g. "required"
+	Param   string `json:"param,omitempty"` // the tag's parameter, e.g. "20" for max=20
+	Value   string `json:"value,omitempty"` // the rejected value, when the rule is about the value itself, e.g. "9A"
+	Message string `json:"message"`
 }
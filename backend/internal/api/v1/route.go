@@ -5,7 +5,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.Engine, flightHandler *handler.FlightHandler) {
+func RegisterRoutes(r *gin.Engine, flightHandler *handler.FlightHandler, voucherHandler *handler.VoucherHandler, idempotency gin.HandlerFunc) {
 	r.POST("/api/check", flightHandler.CheckFlight)
-	r.POST("/api/generate", flightHandler.Generate)
+	r.POST("/api/generate", idempotency, flightHandler.Generate)
+	r.GET("/api/aircraft", flightHandler.ListAircraft)
+	r.GET("/api/assignments", flightHandler.ListAssignments)
+	r.GET("/api/flights/:flightNumber/:date/assignment", flightHandler.GetAssignment)
+	r.DELETE("/api/flights/:flightNumber/:date/assignment", flightHandler.RevokeAssignment)
+	r.DELETE("/api/flights/:flightNumber/:date/assignment/seats/:seat", flightHandler.RevokeSeat)
+	r.GET("/api/flights/:flightNumber/:date/assignment/seats/:seat/qr", voucherHandler.GetSeatQR)
+	r.GET("/api/flights/:flightNumber/:date/inventory", flightHandler.GetSeatInventory)
+	r.GET("/api/flights/:flightNumber/:date/history", flightHandler.GetHistory)
+	r.GET("/api/flights/:flightNumber/:date/voucher.pdf", voucherHandler.GetVoucherPDF)
+	r.GET("/api/vouchers/:code", voucherHandler.GetVoucher)
+	r.POST("/api/vouchers/:code/redeem", voucherHandler.RedeemVoucher)
+	r.GET("/.well-known/jwks.json", voucherHandler.GetVoucherKeys)
 }
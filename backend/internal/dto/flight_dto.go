@@ -1,6 +1,9 @@
 package dto
 
-import "bookcabin-voucher/internal/model"
+import (
+	"bookcabin-voucher/internal/model"
+	"time"
+)
 
 type CheckFlightRequest struct {
 	FlightNumber string   `json:"flightNumber" binding:"required,flight_number"`
@@ -25,9 +28,213 @@ type GenerateRequest struct {
 	Date          string             `json:"date" binding:"required,datetime=02-01-06"` //DD-MM-YY
 	Aircraft      model.AircraftType `json:"aircraft" binding:"required,aircraft_enum"`
 	SeatsToChange []string           `json:"seats"`
+	Strategy      string             `json:"strategy" binding:"omitempty,seat_strategy"`
+	Count         int                `json:"count" binding:"omitempty,min=1,max=20"` // new assignments only, defaults per aircraft
+	Meta          RequestMeta        `json:"-"`
+}
+
+// RequestMeta describes who made a request; it is stored with audit events.
+type RequestMeta struct {
+	Actor     string // X-Actor header, or the body's actor where the request has one
+	RequestID string
+	ClientIP  string
+	UserAgent string
 }
 
 type GenerateResponse struct {
-	Success bool     `json:"success"`
-	Seats   []string `json:"seats"`
+	Success  bool              `json:"success"`
+	Seats    []string          `json:"seats"`
+	Vouchers []VoucherResponse `json:"vouchers"` // the seats with their voucher codes, same order as Seats
+}
+
+type VoucherResponse struct {
+	Seat  string `json:"seat"`
+	Code  string `json:"code"`
+	Token string `json:"token,omitempty"` // the voucher as a signed compact JWS, checked offline against /.well-known/jwks.json
+}
+
+// FlightPathRequest identifies a flight from the /api/flights/:flightNumber/:date path.
+type FlightPathRequest struct {
+	FlightNumber string `uri:"flightNumber" binding:"required,flight_number"`
+	Date         string `uri:"date" binding:"required,datetime=02-01-06"`
+}
+
+// SeatPathRequest identifies one seat from the /api/flights/:flightNumber/:date/assignment/seats/:seat path.
+type SeatPathRequest struct {
+	FlightPathRequest
+	Seat string `uri:"seat" binding:"required,alphanum,uppercase,max=4"`
+}
+
+// SeatQRRequest picks what the QR code of a seat holds and how it is drawn.
+type SeatQRRequest struct {
+	Format  string `form:"format" binding:"omitempty,oneof=png svg"`          // png when unset
+	Payload string `form:"payload" binding:"omitempty,oneof=code token bcbp"` // code when unset
+	Size    int    `form:"size" binding:"omitempty,min=64,max=2048"`          // pixels, 256 when unset
+	From    string `form:"from" binding:"omitempty,alpha,uppercase,len=3"`    // boarding pass route, blank when unset
+	To      string `form:"to" binding:"omitempty,alpha,uppercase,len=3"`
+}
+
+// RevokeRequest records who revokes vouchers and why.
+type RevokeRequest struct {
+	Actor  string      `json:"actor" binding:"required,max=100"`
+	Reason string      `json:"reason" binding:"required,max=255"`
+	Meta   RequestMeta `json:"-"`
+}
+
+type AssignmentEventResponse struct {
+	Type      string    `json:"type"`
+	SeatsFrom []string  `json:"seatsFrom,omitempty"`
+	SeatsTo   []string  `json:"seatsTo,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Actor     string    `json:"actor,omitempty"`
+	RequestID string    `json:"requestId,omitempty"`
+	ClientIP  string    `json:"clientIp,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+type HistoryResponse struct {
+	FlightNumber string                    `json:"flightNumber"`
+	Date         string                    `json:"date"`
+	Events       []AssignmentEventResponse `json:"events"`
+}
+
+type SeatAssignmentResponse struct {
+	Seat     string    `json:"seat"`
+	Code     string    `json:"code,omitempty"` // voucher code, empty for seats issued before codes existed
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+type AssignmentResponse struct {
+	CrewName     string                   `json:"name"`
+	CrewID       string                   `json:"id"`
+	FlightNumber string                   `json:"flightNumber"`
+	Date         string                   `json:"date"`
+	Aircraft     string                   `json:"aircraft"`
+	Seats        []SeatAssignmentResponse `json:"seats"`
+	CreatedAt    time.Time                `json:"createdAt"`
+	UpdatedAt    time.Time                `json:"updatedAt"` // last time a seat was issued
+}
+
+type ListAssignmentsRequest struct {
+	From         string `form:"from" binding:"omitempty,datetime=02-01-06"` // flight date, inclusive
+	To           string `form:"to" binding:"omitempty,datetime=02-01-06"`   // flight date, inclusive
+	CrewID       string `form:"crewId"`
+	Airline      string `form:"airline" binding:"omitempty,len=2,alphanum,uppercase"`
+	FlightNumber string `form:"flightNumber" binding:"omitempty,alphanum,uppercase,max=6"` // prefix
+	Aircraft     string `form:"aircraft" binding:"omitempty,aircraft_enum"`
+	Sort         string `form:"sort" binding:"omitempty,oneof=flightDate -flightDate createdAt -createdAt flightNumber -flightNumber"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor       string `form:"cursor"`
+}
+
+type ListAssignmentsResponse struct {
+	Assignments []AssignmentResponse `json:"assignments"`
+	NextCursor  string               `json:"nextCursor,omitempty"`
+}
+
+// AssignmentSort is the column a listing is ordered by; ties are broken by ID.
+type AssignmentSort string
+
+const (
+	SortByCreatedAt    AssignmentSort = "createdAt"
+	SortByFlightDate   AssignmentSort = "flightDate"
+	SortByFlightNumber AssignmentSort = "flightNumber"
+)
+
+// AssignmentCursor is the position of the last row of the previous page.
+type AssignmentCursor struct {
+	Value string `json:"v,omitempty"` // sort column value, empty when sorting by creation
+	ID    uint   `json:"id"`
+}
+
+type AssignmentFilter struct {
+	FromDay            string // YYYY-MM-DD, inclusive
+	ToDay              string // YYYY-MM-DD, inclusive
+	CrewID             string
+	FlightNumberPrefix []string
+	Aircraft           model.AircraftType
+	Sort               AssignmentSort
+	Descending         bool
+	After              *AssignmentCursor
+	Limit              int
+}
+
+type SeatInventoryRequest struct {
+	FlightNumber string `uri:"flightNumber" binding:"required,flight_number"`
+	Date         string `uri:"date" binding:"required,datetime=02-01-06"`
+	Aircraft     string `form:"aircraft" binding:"omitempty,aircraft_enum"`
+}
+
+type SeatStatusResponse struct {
+	Seat   string `json:"seat"`
+	Status string `json:"status"`
+}
+
+type SeatInventoryResponse struct {
+	FlightNumber string               `json:"flightNumber"`
+	Date         string               `json:"date"`
+	Aircraft     string               `json:"aircraft"`
+	Capacity     int                  `json:"capacity"`
+	Free         int                  `json:"free"`
+	Issued       int                  `json:"issued"`
+	Seats        []SeatStatusResponse `json:"seats"`
+}
+
+type AircraftResponse struct {
+	Type            string   `json:"type"`
+	Aliases         []string `json:"aliases"`
+	Seats           int      `json:"seats"`
+	DefaultVouchers int      `json:"defaultVouchers"`
+	MaxVouchers     int      `json:"maxVouchers"`
+}
+
+type ListAircraftResponse struct {
+	Aircraft []AircraftResponse `json:"aircraft"`
+}
+
+// VoucherPathRequest identifies a voucher from the /api/vouchers/:code path.
+type VoucherPathRequest struct {
+	Code string `uri:"code" binding:"required,voucher_code"`
+}
+
+// RedeemRequest names the seat the holder presents the voucher for, which must
+// match the seat it was issued for, and where it is used.
+type RedeemRequest struct {
+	FlightNumber string      `json:"flightNumber" binding:"required,flight_number"`
+	Date         string      `json:"date" binding:"required,datetime=02-01-06"`
+	Seat         string      `json:"seat" binding:"required,alphanum,uppercase,max=4"`
+	Station      string      `json:"station" binding:"required,alpha,uppercase,min=3,max=4"` // IATA or ICAO airport code
+	Actor        string      `json:"actor" binding:"max=100"`
+	Meta         RequestMeta `json:"-"`
+}
+
+type VoucherStatusResponse struct {
+	Code         string     `json:"code"`
+	Status       string     `json:"status"` // issued, redeemed or revoked
+	CrewName     string     `json:"name"`
+	CrewID       string     `json:"id"`
+	FlightNumber string     `json:"flightNumber"`
+	Date         string     `json:"date"`
+	Aircraft     string     `json:"aircraft"`
+	Seat         string     `json:"seat"`
+	IssuedAt     time.Time  `json:"issuedAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	RedeemedAt   *time.Time `json:"redeemedAt,omitempty"`
+	Station      string     `json:"station,omitempty"`
+	RedeemedBy   string     `json:"redeemedBy,omitempty"`
+}
+
+// VoucherKeySetResponse is a JSON Web Key Set of the keys voucher tokens are signed with.
+type VoucherKeySetResponse struct {
+	Keys []VoucherKeyResponse `json:"keys"`
+}
+
+type VoucherKeyResponse struct {
+	Kty string `json:"kty"` // OKP
+	Crv string `json:"crv"` // Ed25519
+	Kid string `json:"kid"`
+	Alg string `json:"alg"` // EdDSA
+	Use string `json:"use"` // sig
+	X   string `json:"x"`   // the public key, base64url without padding
 }
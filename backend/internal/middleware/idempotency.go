@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bookcabin-voucher/internal/api/model"
+	serviceModel "bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/repository"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyMiddleware answers a retried request carrying the same
+// Idempotency-Key with the stored response of the first one, byte for byte,
+// for ttl after it was first seen. Requests without the header pass through.
+// Server errors and panics are not stored, so the client may retry those.
+func IdempotencyMiddleware(store repository.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Idempotency-Key must be at most 255 characters",
+				Code:  model.CodeInvalidRequest,
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Invalid input: " + err.Error(),
+				Code:  model.CodeInvalidRequest,
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(c.Request.Method, c.Request.URL.Path, body)
+		now := time.Now()
+		existing, err := store.Reserve(c.Request.Context(), &serviceModel.IdempotencyRecord{
+			Key:         key,
+			RequestHash: hash,
+			ExpiresAt:   now.Add(ttl),
+		}, now)
+		if err != nil {
+			log.Printf("[Idempotency] %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, model.ErrorResponse{Error: err.Error(), Code: model.CodeInternal})
+			return
+		}
+		if existing != nil {
+			replay(c, existing, hash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// the outcome is stored even when the client has gone away meanwhile
+		ctx := context.WithoutCancel(c.Request.Context())
+		defer func() {
+			// a panicking handler has no response to store, so free the key
+			// for a retry instead of holding it until it expires
+			if p := recover(); p != nil {
+				if err := store.Release(ctx, key); err != nil {
+					log.Printf("[Idempotency] %v", err)
+				}
+				panic(p)
+			}
+		}()
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			err = store.Release(ctx, key)
+		} else {
+			err = store.Complete(ctx, key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("[Idempotency] %v", err)
+		}
+	}
+}
+
+// replay answers a request whose key was seen before.
+func replay(c *gin.Context, record *serviceModel.IdempotencyRecord, hash string) {
+	switch {
+	case record.RequestHash != hash:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, model.ErrorResponse{
+			Error: "Idempotency-Key was already used for a different request",
+			Code:  model.CodeIdempotencyKeyReused,
+		})
+	case !record.Completed():
+		c.AbortWithStatusJSON(http.StatusConflict, model.ErrorResponse{
+			Error: "a request with this Idempotency-Key is still being processed",
+			Code:  model.CodeIdempotencyInProgress,
+		})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(record.StatusCode, record.ContentType, record.Body)
+		c.Abort()
+	}
+}
+
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it through.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
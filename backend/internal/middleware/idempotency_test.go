@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bookcabin-voucher/infrastructure/persistent"
+	"bookcabin-voucher/internal/api/model"
+	"bookcabin-voucher/internal/migration"
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupIdempotentRouter(t *testing.T, ttl time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	_, err = migration.Up(t.Context(), db)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.POST("/api/generate", IdempotencyMiddleware(persistent.NewIdempotencyRepository(db), ttl), handler)
+	return r
+}
+
+func postGenerate(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	calls := 0
+	r := setupIdempotentRouter(t, time.Hour, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"success": true, "call": calls})
+	})
+
+	first := postGenerate(r, "abc", `{"flightNumber":"JT692"}`)
+	second := postGenerate(r, "abc", `{"flightNumber":"JT692"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, first.Body.Bytes(), second.Body.Bytes())
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+
+	// without a key every request runs
+	postGenerate(r, "", `{"flightNumber":"JT692"}`)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_KeyReusedForOtherRequest(t *testing.T) {
+	r := setupIdempotentRouter(t, time.Hour, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	postGenerate(r, "abc", `{"flightNumber":"JT692"}`)
+	resp := postGenerate(r, "abc", `{"flightNumber":"JT693"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	var body model.ErrorResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, model.CodeIdempotencyKeyReused, body.Code)
+}
+
+func TestIdempotencyMiddleware_ServerErrorsAreRetried(t *testing.T) {
+	calls := 0
+	r := setupIdempotentRouter(t, time.Hour, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, postGenerate(r, "abc", `{}`).Code)
+	assert.Equal(t, http.StatusOK, postGenerate(r, "abc", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_PanicReleasesKey(t *testing.T) {
+	calls := 0
+	r := setupIdempotentRouter(t, time.Hour, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	// the panic is passed on to whichever middleware recovers it
+	assert.PanicsWithValue(t, "boom", func() { postGenerate(r, "abc", `{}`) })
+	assert.Equal(t, http.StatusOK, postGenerate(r, "abc", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_ExpiredKeyRunsAgain(t *testing.T) {
+	calls := 0
+	r := setupIdempotentRouter(t, -time.Second, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	postGenerate(r, "abc", `{}`)
+	resp := postGenerate(r, "abc", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, resp.Header().Get(IdempotentReplayedHeader))
+}
@@ -1,24 +1,272 @@
+// Package migration applies the numbered SQL files under sql/<dialect>.
+//
+// Every version has a NNNN_name.up.sql and a NNNN_name.down.sql file for each
+// supported dialect. Statements end with a semicolon at the end of a line, so
+// trigger and function bodies keep their inner semicolons mid-line. Applied
+// versions are recorded in the schema_migrations table.
 package migration
 
 import (
-	"bookcabin-voucher/internal/model"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
 	"gorm.io/gorm"
-	"log"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func Migrate(db *gorm.DB) {
-	err := db.AutoMigrate(
-		&model.FlightAssignment{},
-		&model.FlightSeatAssignment{},
-	)
+//go:embed sql
+var files embed.FS
 
+// ErrNotMigrated is returned by Check while migrations are still pending.
+var ErrNotMigrated = errors.New("database schema is not up to date")
+
+var (
+	fileRegex    = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	statementEnd = regexp.MustCompile(`;[ \t]*(\r?\n|$)`)
+)
+
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`
+
+// Migration is one numbered schema change.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status reports whether a migration has been applied, and when.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load returns the migrations for a dialect ordered by version.
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql/"+dialect)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s databases", dialect)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s/%s", dialect, entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, match[2])
+		}
+
+		script, err := fs.ReadFile(files, "sql/"+dialect+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			m.up = string(script)
+		} else {
+			m.down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the ones it ran.
+// Each migration runs in its own transaction together with its version record.
+func Up(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Exec(createVersionTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, m.up); err != nil {
+				return err
+			}
+			return tx.Create(&appliedMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migration %s failed: %w", m, err)
+		}
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// Down rolls back the latest steps applied migrations and returns them,
+// newest first.
+func Down(ctx context.Context, db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	var ran []Migration
+	for _, version := range versions[:min(steps, len(versions))] {
+		m, ok := known[version]
+		if !ok {
+			return ran, fmt.Errorf("applied migration %d is not known to this build", version)
+		}
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, m.down); err != nil {
+				return err
+			}
+			return tx.Delete(&appliedMigration{Version: m.Version}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("rolling back migration %s failed: %w", m, err)
+		}
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// List returns every known migration with the time it was applied, if it was.
+func List(ctx context.Context, db *gorm.DB) ([]Status, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		status := Status{Migration: m}
+		if at, ok := applied[m.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check returns ErrNotMigrated, naming the pending migrations, unless the
+// database is at the latest version.
+func Check(ctx context.Context, db *gorm.DB) error {
+	statuses, err := List(ctx, db)
 	if err != nil {
-		log.Fatalf("Failed to run AutoMigrate: %v", err)
+		return err
+	}
+	var pending []string
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.String())
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w, pending: %s", ErrNotMigrated, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// appliedVersions reads schema_migrations, treating a missing table as an empty database.
+func appliedVersions(ctx context.Context, db *gorm.DB) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time)
+	if !db.WithContext(ctx).Migrator().HasTable(appliedMigration{}.TableName()) {
+		return applied, nil
 	}
+	var rows []appliedMigration
+	if err := db.WithContext(ctx).Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+func execScript(tx *gorm.DB, script string) error {
+	for _, statement := range statements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return fmt.Errorf("%w\n%s", err, statement)
+		}
+	}
+	return nil
+}
 
-	// Enforce unique constraint on (flight_number, date)
-	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_flight_number_date ON flight_assignments(flight_number, flight_date)")
-	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_flight_assignment_id_seat ON flight_seat_assignments(flight_assignment_id, seat)")
+// statements splits a migration file into its statements, dropping comment lines.
+func statements(script string) []string {
+	var code []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			code = append(code, line)
+		}
+	}
 
-	log.Println("Database migration completed.")
+	var out []string
+	for _, statement := range statementEnd.Split(strings.Join(code, "\n"), -1) {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			out = append(out, statement)
+		}
+	}
+	return out
 }
@@ -0,0 +1,138 @@
+package migration
+
+import (
+	"bookcabin-voucher/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	return db
+}
+
+func names(migrations []Migration) []string {
+	out := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m.String())
+	}
+	return out
+}
+
+func TestLoad_DialectsShareVersions(t *testing.T) {
+	sqliteMigrations, err := Load("sqlite")
+	require.NoError(t, err)
+	require.NotEmpty(t, sqliteMigrations)
+
+	for _, dialect := range []string{"postgres", "mysql"} {
+		migrations, err := Load(dialect)
+		require.NoError(t, err, dialect)
+		assert.Equal(t, names(sqliteMigrations), names(migrations), dialect)
+		for _, m := range migrations {
+			assert.NotEmpty(t, statements(m.up), "%s %s up", dialect, m)
+			assert.NotEmpty(t, statements(m.down), "%s %s down", dialect, m)
+		}
+	}
+
+	_, err = Load("oracle")
+	assert.Error(t, err)
+}
+
+func TestStatements(t *testing.T) {
+	script := `-- leading comment; ignored
+CREATE TABLE a (
+    id INTEGER -- trailing comments stay
+);
+CREATE TRIGGER t BEFORE DELETE ON a
+BEGIN SELECT RAISE(ABORT, 'no'); END;
+
+UPDATE a SET id = 1;`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n    id INTEGER -- trailing comments stay\n)",
+		"CREATE TRIGGER t BEFORE DELETE ON a\nBEGIN SELECT RAISE(ABORT, 'no'); END",
+		"UPDATE a SET id = 1",
+	}, statements(script))
+}
+
+func TestUpDown(t *testing.T) {
+	db := setupTestDB(t)
+	all, err := Load("sqlite")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, Check(t.Context(), db), ErrNotMigrated)
+
+	ran, err := Up(t.Context(), db)
+	require.NoError(t, err)
+	assert.Equal(t, names(all), names(ran))
+	assert.NoError(t, Check(t.Context(), db))
+	assert.True(t, db.Migrator().HasTable("idempotency_records"))
+	assert.True(t, db.Migrator().HasIndex("flight_assignments", "idx_flight_assignments_flight_number"))
+
+	ran, err = Up(t.Context(), db)
+	require.NoError(t, err)
+	assert.Empty(t, ran)
+
+	ran, err = Down(t.Context(), db, 1)
+	require.NoError(t, err)
+	assert.Equal(t, names(all[len(all)-1:]), names(ran))
+	err = Check(t.Context(), db)
+	assert.ErrorIs(t, err, ErrNotMigrated)
+	assert.ErrorContains(t, err, all[len(all)-1].String())
+	assert.False(t, db.Migrator().HasIndex("flight_assignments", "idx_flight_assignments_flight_number"))
+	assert.True(t, db.Migrator().HasTable("voucher_redemptions"))
+
+	statuses, err := List(t.Context(), db)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(all))
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[len(all)-1].AppliedAt)
+
+	ran, err = Down(t.Context(), db, len(all)+5)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(all)-1)
+	assert.False(t, db.Migrator().HasTable("flight_assignments"))
+	assert.False(t, db.Migrator().HasTable("flight_locks"))
+
+	_, err = Down(t.Context(), db, 0)
+	assert.Error(t, err)
+
+	_, err = Up(t.Context(), db)
+	require.NoError(t, err)
+	assert.NoError(t, Check(t.Context(), db))
+}
+
+func TestUp_AdoptsAutoMigrateDatabase(t *testing.T) {
+	db := setupTestDB(t)
+
+	// the schema as AutoMigrate left it, including the unconditional unique index
+	require.NoError(t, db.AutoMigrate(
+		&model.FlightAssignment{}, &model.FlightSeatAssignment{}, &model.FlightSeatInventory{},
+		&model.FlightAssignmentEvent{}, &model.IdempotencyRecord{},
+	))
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX idx_flight_number_date ON flight_assignments(flight_number, flight_date)").Error)
+	require.NoError(t, db.Create(&model.FlightAssignment{
+		CrewID: "1", FlightNumber: "JT692", FlightDate: "05-07-25", AircraftType: "ATR",
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}},
+	}).Error)
+	// voucher codes came after AutoMigrate was retired
+	require.NoError(t, db.Migrator().DropIndex(&model.FlightSeatAssignment{}, "Code"))
+	require.NoError(t, db.Migrator().DropColumn(&model.FlightSeatAssignment{}, "Code"))
+
+	_, err := Up(t.Context(), db)
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasIndex("flight_assignments", "idx_flight_number_date"))
+
+	var assignment model.FlightAssignment
+	require.NoError(t, db.First(&assignment).Error)
+	assert.Equal(t, "2025-07-05", assignment.FlightDay)
+
+	var inventory []model.FlightSeatInventory
+	require.NoError(t, db.Find(&inventory).Error)
+	require.Len(t, inventory, 1)
+	assert.Equal(t, model.SeatIssued, inventory[0].Status)
+}
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS flight_assignments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    crew_name VARCHAR(100) NOT NULL,
+    crew_id VARCHAR(50) NOT NULL,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    flight_day VARCHAR(10) NOT NULL DEFAULT '',
+    aircraft_type VARCHAR(50) NOT NULL,
+    created_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    revoked_by VARCHAR(100) NOT NULL DEFAULT '',
+    revoke_reason VARCHAR(255) NOT NULL DEFAULT '',
+    -- MySQL has no partial indexes. active is NULL on revoked rows and NULLs
+    -- never collide, so the unique index only covers live assignments.
+    active TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) STORED,
+    INDEX idx_flight_assignments_crew_id (crew_id),
+    INDEX idx_flight_assignments_flight_day (flight_day),
+    INDEX idx_flight_assignments_aircraft_type (aircraft_type),
+    INDEX idx_flight_assignments_deleted_at (deleted_at),
+    UNIQUE INDEX idx_active_flight_number_date (flight_number, flight_date, active)
+);
+
+CREATE TABLE IF NOT EXISTS flight_seat_assignments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    flight_assignment_id BIGINT UNSIGNED NOT NULL,
+    seat VARCHAR(10) NOT NULL,
+    created_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    revoked_by VARCHAR(100) NOT NULL DEFAULT '',
+    revoke_reason VARCHAR(255) NOT NULL DEFAULT '',
+    active TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) STORED,
+    INDEX idx_flight_seat_assignments_flight_assignment_id (flight_assignment_id),
+    INDEX idx_flight_seat_assignments_deleted_at (deleted_at),
+    UNIQUE INDEX idx_active_assignment_id_seat (flight_assignment_id, seat, active),
+    CONSTRAINT fk_flight_assignments_seat_assignments FOREIGN KEY (flight_assignment_id)
+        REFERENCES flight_assignments (id) ON DELETE CASCADE
+);
+
+-- Databases created by AutoMigrate may still carry the old unconditional
+-- indexes. MySQL has no DROP INDEX IF EXISTS, so each drop is looked up first.
+SET @drop_index = (SELECT IF(COUNT(*) > 0, 'ALTER TABLE flight_assignments DROP INDEX idx_flight_number_date', 'DO 0')
+    FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'flight_assignments' AND index_name = 'idx_flight_number_date');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
+SET @drop_index = (SELECT IF(COUNT(*) > 0, 'ALTER TABLE flight_seat_assignments DROP INDEX idx_flight_assignment_id_seat', 'DO 0')
+    FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'flight_seat_assignments' AND index_name = 'idx_flight_assignment_id_seat');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
+
+-- Assignments created before flight_day existed
+UPDATE flight_assignments
+SET flight_day = CONCAT('20', substr(flight_date, 7, 2), '-', substr(flight_date, 4, 2), '-', substr(flight_date, 1, 2))
+WHERE flight_day = '';
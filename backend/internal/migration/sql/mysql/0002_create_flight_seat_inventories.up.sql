@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS flight_seat_inventories (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    seat VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_inventory_flight_seat (flight_number, flight_date, seat)
+);
+
+-- Seats issued before the inventory existed are recorded as issued
+INSERT INTO flight_seat_inventories (flight_number, flight_date, seat, status, created_at, updated_at)
+SELECT a.flight_number, a.flight_date, s.seat, 'voucher-issued', s.created_at, s.created_at
+FROM flight_seat_assignments s JOIN flight_assignments a ON a.id = s.flight_assignment_id
+WHERE s.deleted_at IS NULL AND a.deleted_at IS NULL AND NOT EXISTS (
+    SELECT 1 FROM flight_seat_inventories i
+    WHERE i.flight_number = a.flight_number AND i.flight_date = a.flight_date AND i.seat = s.seat
+);
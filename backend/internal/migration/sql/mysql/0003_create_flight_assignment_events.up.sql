@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS flight_assignment_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    flight_assignment_id BIGINT UNSIGNED NOT NULL,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    seats_from TEXT,
+    seats_to TEXT,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(3) NULL,
+    INDEX idx_flight_assignment_events_flight_assignment_id (flight_assignment_id),
+    INDEX idx_event_flight (flight_number, flight_date)
+);
+
+-- The audit log is append-only
+DROP TRIGGER IF EXISTS trg_assignment_events_no_update;
+CREATE TRIGGER trg_assignment_events_no_update BEFORE UPDATE ON flight_assignment_events
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'flight_assignment_events is append-only';
+DROP TRIGGER IF EXISTS trg_assignment_events_no_delete;
+CREATE TRIGGER trg_assignment_events_no_delete BEFORE DELETE ON flight_assignment_events
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'flight_assignment_events is append-only';
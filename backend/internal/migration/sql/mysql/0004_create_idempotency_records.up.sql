@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    body LONGBLOB,
+    created_at DATETIME(3) NULL,
+    expires_at DATETIME(3) NOT NULL,
+    INDEX idx_idempotency_records_expires_at (expires_at)
+);
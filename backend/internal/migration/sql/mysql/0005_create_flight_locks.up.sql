@@ -0,0 +1,8 @@
+-- Writers of a flight's assignment bump its row first, which serialises them
+CREATE TABLE IF NOT EXISTS flight_locks (
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (flight_number, flight_date)
+);
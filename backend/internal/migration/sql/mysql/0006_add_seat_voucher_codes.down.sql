@@ -0,0 +1,2 @@
+DROP INDEX idx_flight_seat_assignments_code ON flight_seat_assignments;
+ALTER TABLE flight_seat_assignments DROP COLUMN code;
@@ -0,0 +1,12 @@
+-- One row per used voucher; the unique seat stops a voucher being redeemed twice
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    flight_seat_assignment_id BIGINT UNSIGNED NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    station VARCHAR(10) NOT NULL,
+    redeemed_by VARCHAR(100) NOT NULL DEFAULT '',
+    redeemed_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_voucher_redemptions_flight_seat_assignment_id (flight_seat_assignment_id),
+    CONSTRAINT fk_voucher_redemptions_seat_assignment FOREIGN KEY (flight_seat_assignment_id)
+        REFERENCES flight_seat_assignments (id)
+);
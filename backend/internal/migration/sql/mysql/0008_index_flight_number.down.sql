@@ -0,0 +1 @@
+DROP INDEX idx_flight_assignments_flight_number ON flight_assignments;
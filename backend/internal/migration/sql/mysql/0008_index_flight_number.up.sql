@@ -0,0 +1,2 @@
+-- Serves the flight number prefix filter; LIKE 'X%' is a range scan on it
+CREATE INDEX idx_flight_assignments_flight_number ON flight_assignments(flight_number);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flight_assignment_events;
+DROP FUNCTION IF EXISTS reject_append_only_change();
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS flight_assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    flight_assignment_id BIGINT NOT NULL,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    seats_from TEXT,
+    seats_to TEXT,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_flight_assignment_events_flight_assignment_id ON flight_assignment_events(flight_assignment_id);
+CREATE INDEX IF NOT EXISTS idx_event_flight ON flight_assignment_events(flight_number, flight_date);
+
+-- The audit log is append-only
+CREATE OR REPLACE FUNCTION reject_append_only_change() RETURNS trigger AS $$
+BEGIN RAISE EXCEPTION '% is append-only', TG_TABLE_NAME; END
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS trg_assignment_events_no_update ON flight_assignment_events;
+CREATE TRIGGER trg_assignment_events_no_update BEFORE UPDATE ON flight_assignment_events
+FOR EACH ROW EXECUTE FUNCTION reject_append_only_change();
+DROP TRIGGER IF EXISTS trg_assignment_events_no_delete ON flight_assignment_events;
+CREATE TRIGGER trg_assignment_events_no_delete BEFORE DELETE ON flight_assignment_events
+FOR EACH ROW EXECUTE FUNCTION reject_append_only_change();
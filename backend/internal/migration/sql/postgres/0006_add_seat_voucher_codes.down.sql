@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_flight_seat_assignments_code;
+ALTER TABLE flight_seat_assignments DROP COLUMN code;
@@ -0,0 +1,3 @@
+-- Serves the flight number prefix filter; LIKE 'X%' only uses a btree index
+-- with pattern ops unless the database collation is C.
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_flight_number ON flight_assignments(flight_number text_pattern_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flight_seat_assignments;
+DROP TABLE IF EXISTS flight_assignments;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS flight_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    crew_name VARCHAR(100) NOT NULL,
+    crew_id VARCHAR(50) NOT NULL,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    flight_day VARCHAR(10) NOT NULL DEFAULT '',
+    aircraft_type VARCHAR(50) NOT NULL,
+    created_at DATETIME,
+    deleted_at DATETIME,
+    revoked_by VARCHAR(100) NOT NULL DEFAULT '',
+    revoke_reason VARCHAR(255) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_crew_id ON flight_assignments(crew_id);
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_flight_day ON flight_assignments(flight_day);
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_aircraft_type ON flight_assignments(aircraft_type);
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_deleted_at ON flight_assignments(deleted_at);
+
+CREATE TABLE IF NOT EXISTS flight_seat_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flight_assignment_id INTEGER NOT NULL REFERENCES flight_assignments(id) ON DELETE CASCADE,
+    seat VARCHAR(10) NOT NULL,
+    created_at DATETIME,
+    deleted_at DATETIME,
+    revoked_by VARCHAR(100) NOT NULL DEFAULT '',
+    revoke_reason VARCHAR(255) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_flight_seat_assignments_flight_assignment_id ON flight_seat_assignments(flight_assignment_id);
+CREATE INDEX IF NOT EXISTS idx_flight_seat_assignments_deleted_at ON flight_seat_assignments(deleted_at);
+
+-- Unique among live rows, so a revoked assignment or seat can be issued again.
+-- Databases created by AutoMigrate still carry the old unconditional indexes.
+DROP INDEX IF EXISTS idx_flight_number_date;
+DROP INDEX IF EXISTS idx_flight_assignment_id_seat;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_active_flight_number_date ON flight_assignments(flight_number, flight_date) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_active_assignment_id_seat ON flight_seat_assignments(flight_assignment_id, seat) WHERE deleted_at IS NULL;
+
+-- Assignments created before flight_day existed
+UPDATE flight_assignments
+SET flight_day = '20' || substr(flight_date, 7, 2) || '-' || substr(flight_date, 4, 2) || '-' || substr(flight_date, 1, 2)
+WHERE flight_day = '';
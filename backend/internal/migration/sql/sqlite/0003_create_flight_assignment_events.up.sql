@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS flight_assignment_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flight_assignment_id INTEGER NOT NULL,
+    flight_number VARCHAR(20) NOT NULL,
+    flight_date VARCHAR(10) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    seats_from TEXT,
+    seats_to TEXT,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_flight_assignment_events_flight_assignment_id ON flight_assignment_events(flight_assignment_id);
+CREATE INDEX IF NOT EXISTS idx_event_flight ON flight_assignment_events(flight_number, flight_date);
+
+-- The audit log is append-only
+CREATE TRIGGER IF NOT EXISTS trg_assignment_events_no_update BEFORE UPDATE ON flight_assignment_events
+BEGIN SELECT RAISE(ABORT, 'flight_assignment_events is append-only'); END;
+CREATE TRIGGER IF NOT EXISTS trg_assignment_events_no_delete BEFORE DELETE ON flight_assignment_events
+BEGIN SELECT RAISE(ABORT, 'flight_assignment_events is append-only'); END;
@@ -0,0 +1,3 @@
+-- Seats issued before this version keep a NULL code; NULLs never collide
+ALTER TABLE flight_seat_assignments ADD COLUMN code VARCHAR(20);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flight_seat_assignments_code ON flight_seat_assignments(code);
@@ -0,0 +1,10 @@
+-- One row per used voucher; the unique seat stops a voucher being redeemed twice
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flight_seat_assignment_id INTEGER NOT NULL REFERENCES flight_seat_assignments(id),
+    code VARCHAR(20) NOT NULL,
+    station VARCHAR(10) NOT NULL,
+    redeemed_by VARCHAR(100) NOT NULL DEFAULT '',
+    redeemed_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_voucher_redemptions_flight_seat_assignment_id ON voucher_redemptions(flight_seat_assignment_id);
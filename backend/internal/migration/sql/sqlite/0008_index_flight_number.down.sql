@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_flight_assignments_flight_number;
@@ -0,0 +1,3 @@
+-- Serves the flight number prefix filter; LIKE is case-insensitive in SQLite,
+-- so only a NOCASE index can be used for it.
+CREATE INDEX IF NOT EXISTS idx_flight_assignments_flight_number ON flight_assignments(flight_number COLLATE NOCASE);
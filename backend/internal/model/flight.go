@@ -1,32 +1,43 @@
 package model
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
+// AircraftType is the key of an aircraft in the seat layout file.
 type AircraftType string
 
-const (
-	ATR          AircraftType = "ATR"
-	Airbus320    AircraftType = "Airbus 320"
-	Boeing737Max AircraftType = "Boeing 737 Max"
-)
-
 type FlightAssignment struct {
 	ID           uint         `gorm:"primaryKey"`
 	CrewName     string       `gorm:"type:varchar(100);not null"`
-	CrewID       string       `gorm:"type:varchar(50);not null"`
+	CrewID       string       `gorm:"type:varchar(50);not null;index"`
 	FlightNumber string       `gorm:"type:varchar(20);not null"`
-	FlightDate   string       `gorm:"type:date;not null"`
-	AircraftType AircraftType `gorm:"type:varchar(50);not null"`
+	FlightDate   string       `gorm:"type:varchar(10);not null"`                  // DD-MM-YY, kept as text so SQLite does not parse it as a time
+	FlightDay    string       `gorm:"type:varchar(10);not null;default:'';index"` // FlightDate as YYYY-MM-DD, sortable for range queries
+	AircraftType AircraftType `gorm:"type:varchar(50);not null;index"`
 
 	SeatAssignments []FlightSeatAssignment `gorm:"foreignKey:FlightAssignmentID;constraint:OnDelete:CASCADE;"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// set when the assignment is revoked; revoked rows are kept for audit
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	RevokedBy    string         `gorm:"type:varchar(100);not null;default:''"`
+	RevokeReason string         `gorm:"type:varchar(255);not null;default:''"`
 }
 
 type FlightSeatAssignment struct {
 	ID                 uint   `gorm:"primaryKey"`
 	FlightAssignmentID uint   `gorm:"not null;index"` // FK
-	Seat               string `gorm:"type:text;not null"`
-	
+	Seat               string `gorm:"type:varchar(10);not null"`
+	Code               string `gorm:"type:varchar(20);default:null;uniqueIndex"` // voucher code, NULL for seats issued before codes existed
+	Token              string `gorm:"-"`                                         // signed voucher token, only set on the seats returned by GenerateAndAssignSeats
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// set when the seat is revoked or replaced by a re-roll
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	RevokedBy    string         `gorm:"type:varchar(100);not null;default:''"`
+	RevokeReason string         `gorm:"type:varchar(255);not null;default:''"`
 }
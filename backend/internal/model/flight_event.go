@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// AssignmentEventType is the kind of change recorded in the audit log.
+type AssignmentEventType string
+
+const (
+	EventCreated     AssignmentEventType = "created"
+	EventReRolled    AssignmentEventType = "re-rolled"
+	EventSeatRevoked AssignmentEventType = "seat-revoked"
+	EventRevoked     AssignmentEventType = "revoked"
+	EventRedeemed    AssignmentEventType = "voucher-redeemed"
+)
+
+// FlightAssignmentEvent is one entry of the append-only audit log of a flight's
+// assignments. Rows are never updated or deleted, the migration enforces it.
+type FlightAssignmentEvent struct {
+	ID                 uint                `gorm:"primaryKey"`
+	FlightAssignmentID uint                `gorm:"not null;index"`
+	FlightNumber       string              `gorm:"type:varchar(20);not null;index:idx_event_flight"`
+	FlightDate         string              `gorm:"type:varchar(10);not null;index:idx_event_flight"` // DD-MM-YY
+	Type               AssignmentEventType `gorm:"type:varchar(20);not null"`
+	SeatsFrom          []string            `gorm:"type:text;serializer:json"` // seats given up by the change
+	SeatsTo            []string            `gorm:"type:text;serializer:json"` // seats issued by the change
+	Reason             string              `gorm:"type:varchar(255);not null;default:''"`
+
+	// who asked for the change
+	Actor     string `gorm:"type:varchar(100);not null;default:''"`
+	RequestID string `gorm:"type:varchar(64);not null;default:''"`
+	ClientIP  string `gorm:"type:varchar(64);not null;default:''"`
+	UserAgent string `gorm:"type:varchar(255);not null;default:''"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// SeatStatus is the state of a seat on a specific flight.
+type SeatStatus string
+
+const (
+	SeatFree   SeatStatus = "free"
+	SeatIssued SeatStatus = "voucher-issued"
+)
+
+// FlightSeatInventory records the status of one seat on one flight. Seats
+// without a row are free; a row is kept as free once its voucher is released.
+type FlightSeatInventory struct {
+	ID           uint       `gorm:"primaryKey"`
+	FlightNumber string     `gorm:"type:varchar(20);not null"`
+	FlightDate   string     `gorm:"type:varchar(10);not null"` // DD-MM-YY
+	Seat         string     `gorm:"type:varchar(10);not null"`
+	Status       SeatStatus `gorm:"type:varchar(20);not null"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// SeatInventory summarises the occupancy of a flight.
+type SeatInventory struct {
+	FlightNumber string
+	FlightDate   string
+	Aircraft     AircraftType
+	Capacity     int                // issuable seats in the aircraft layout, blocked seats excluded
+	Counts       map[SeatStatus]int // seats per status, free included
+	Seats        []FlightSeatInventory
+}
@@ -1,7 +1,120 @@
 package model
 
+import "fmt"
+
+// AircraftLayout describes the physical cabin of an aircraft type.
+// StartRow/EndRow/Seats define the default grid, the remaining fields
+// carve out rows and seats that do not exist or must never be issued.
 type AircraftLayout struct {
+	Aliases         []string         `json:"aliases,omitempty"`         // alternative names accepted for the aircraft, e.g. "A320"
+	DefaultVouchers int              `json:"defaultVouchers,omitempty"` // vouchers issued when a request gives no count
+	MaxVouchers     int              `json:"maxVouchers,omitempty"`     // upper bound on vouchers per flight, 0 for no limit
+	StartRow        int              `json:"startRow"`
+	EndRow          int              `json:"endRow"`
+	Seats           []string         `json:"seats"`
+	SkippedRows     []int            `json:"skippedRows,omitempty"`  // rows absent from the cabin, e.g. 13 or galley gaps
+	ExitRows        []int            `json:"exitRows,omitempty"`     // rows next to an emergency exit
+	BlockedSeats    []string         `json:"blockedSeats,omitempty"` // seats that exist but are never issued, e.g. "1A"
+	AisleAfter      []string         `json:"aisleAfter,omitempty"`   // letters followed by an aisle, e.g. "C" for ABC-DEF
+	RowSeats        map[int][]string `json:"rowSeats,omitempty"`     // per-row seat letters overriding sections and defaults
+	Sections        []CabinSection   `json:"sections,omitempty"`
+}
+
+// AircraftInfo summarises a supported aircraft type.
+type AircraftInfo struct {
+	Type            AircraftType
+	Aliases         []string
+	Seats           int // number of issuable seats
+	DefaultVouchers int
+	MaxVouchers     int
+}
+
+// CabinSection is a contiguous block of rows sharing a class of service.
+// Seats overrides the layout's default seat letters for those rows.
+type CabinSection struct {
+	Name     string   `json:"name"`
 	StartRow int      `json:"startRow"`
 	EndRow   int      `json:"endRow"`
-	Seats    []string `json:"seats"`
+	Seats    []string `json:"seats,omitempty"`
+}
+
+// Seat is a single physical seat resolved from an AircraftLayout.
+type Seat struct {
+	Row     int
+	Column  int // position of the letter within its row, 0 being the leftmost seat
+	Letter  string
+	Section string
+	ExitRow bool
+	Window  bool
+	Aisle   bool
+}
+
+// Code returns the seat in the row+letter form used across the API, e.g. "12C".
+func (s Seat) Code() string {
+	return fmt.Sprintf("%d%s", s.Row, s.Letter)
+}
+
+// SeatMap enumerates every issuable seat in the layout ordered front to back,
+// then by the letter order declared for the row.
+func (l AircraftLayout) SeatMap() []Seat {
+	skipped := make(map[int]bool, len(l.SkippedRows))
+	for _, row := range l.SkippedRows {
+		skipped[row] = true
+	}
+	exits := make(map[int]bool, len(l.ExitRows))
+	for _, row := range l.ExitRows {
+		exits[row] = true
+	}
+	blocked := make(map[string]bool, len(l.BlockedSeats))
+	for _, seat := range l.BlockedSeats {
+		blocked[seat] = true
+	}
+	aisleAfter := make(map[string]bool, len(l.AisleAfter))
+	for _, letter := range l.AisleAfter {
+		aisleAfter[letter] = true
+	}
+
+	var seats []Seat
+	for row := l.StartRow; row <= l.EndRow; row++ {
+		if skipped[row] {
+			continue
+		}
+		section, letters := l.rowDefinition(row)
+		for i, letter := range letters {
+			seat := Seat{
+				Row:     row,
+				Column:  i,
+				Letter:  letter,
+				Section: section,
+				ExitRow: exits[row],
+				Window:  i == 0 || i == len(letters)-1,
+				Aisle:   aisleAfter[letter] || (i > 0 && aisleAfter[letters[i-1]]),
+			}
+			if blocked[seat.Code()] {
+				continue
+			}
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// rowDefinition resolves the section name and seat letters for a row.
+// Precedence is RowSeats, then the enclosing section, then the default Seats.
+func (l AircraftLayout) rowDefinition(row int) (string, []string) {
+	section := ""
+	letters := l.Seats
+	for _, s := range l.Sections {
+		if row >= s.StartRow && row <= s.EndRow {
+			section = s.Name
+			if len(s.Seats) > 0 {
+				letters = s.Seats
+			}
+			break
+		}
+	}
+	if override, ok := l.RowSeats[row]; ok {
+		letters = override
+	}
+	return section, letters
 }
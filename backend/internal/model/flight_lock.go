@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// FlightLock is the row writers of a flight's assignment lock before reading
+// it. Version is bumped by every write transaction, so concurrent requests for
+// the same flight and date queue on the row lock instead of interleaving.
+type FlightLock struct {
+	FlightNumber string `gorm:"primaryKey;type:varchar(20)"`
+	FlightDate   string `gorm:"primaryKey;type:varchar(10)"` // DD-MM-YY
+	Version      int64  `gorm:"not null;default:0"`
+
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
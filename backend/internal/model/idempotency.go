@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key so a retry can be answered without running it again.
+// StatusCode stays 0 while the first request is still being handled.
+type IdempotencyRecord struct {
+	Key         string `gorm:"column:idempotency_key;primaryKey;type:varchar(255)"`
+	RequestHash string `gorm:"type:varchar(64);not null"` // sha256 of method, path and body
+	StatusCode  int    `gorm:"not null;default:0"`
+	ContentType string `gorm:"type:varchar(100);not null;default:''"`
+	Body        []byte
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// Completed reports whether the original request has finished and its response is stored.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"time"
+)
+
+// Voucher is one issued seat as printed for the crew member.
+type Voucher struct {
+	Code         string
+	CrewName     string
+	CrewID       string
+	FlightNumber string
+	FlightDate   string // DD-MM-YY
+	Aircraft     AircraftType
+	Seat         string
+	IssuedAt     time.Time
+	Token        string // signed voucher token, empty when tokens are not issued
+	BCBP         string // the voucher as an IATA boarding pass without route
+}
+
+// VoucherTemplate describes the printed voucher, one page per seat. Sizes and
+// positions are in millimetres from the top-left corner of the page.
+type VoucherTemplate struct {
+	PageWidth  float64          `json:"pageWidth"`
+	PageHeight float64          `json:"pageHeight"`
+	Font       string           `json:"font,omitempty"`   // a PDF core font: Helvetica, Times or Courier
+	Border     bool             `json:"border,omitempty"` // frame the page
+	Elements   []VoucherElement `json:"elements"`
+}
+
+// VoucherElement is a line of text on the voucher. Text is a Go text/template
+// executed against the Voucher, e.g. "Seat {{.Seat}}".
+type VoucherElement struct {
+	Text  string  `json:"text"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Width float64 `json:"width,omitempty"` // 0 stretches to the right margin
+	Size  float64 `json:"size,omitempty"`  // font size in points, 10 when unset
+	Style string  `json:"style,omitempty"` // any of B, I and U
+	Align string  `json:"align,omitempty"` // L, C or R
+	Box   bool    `json:"box,omitempty"`   // draw a frame around the element
+	QR    bool    `json:"qr,omitempty"`    // draw Text as a QR code Width mm square instead of printing it
+}
+
+// What a voucher QR code holds.
+const (
+	QRPayloadCode  = "code"  // the voucher code, looked up through the voucher API
+	QRPayloadToken = "token" // the signed voucher token, checked offline
+	QRPayloadBCBP  = "bcbp"  // an IATA boarding pass for gate scanners
+)
+
+// VoucherState is where a voucher is in its life.
+type VoucherState string
+
+const (
+	VoucherIssued   VoucherState = "issued"
+	VoucherRedeemed VoucherState = "redeemed"
+	VoucherRevoked  VoucherState = "revoked" // the seat was revoked or re-rolled before it was used
+)
+
+// VoucherRedemption records that a voucher was used. A seat is redeemed at
+// most once, the migration enforces it.
+type VoucherRedemption struct {
+	ID                     uint      `gorm:"primaryKey"`
+	FlightSeatAssignmentID uint      `gorm:"not null;uniqueIndex"`
+	Code                   string    `gorm:"type:varchar(20);not null"`
+	Station                string    `gorm:"type:varchar(10);not null"` // airport where the voucher was used, e.g. CGK
+	RedeemedBy             string    `gorm:"type:varchar(100);not null;default:''"`
+	RedeemedAt             time.Time `gorm:"autoCreateTime"`
+}
+
+// IssuedVoucher is a seat issued with a voucher code, whether or not it is
+// still live, together with its assignment and redemption.
+type IssuedVoucher struct {
+	Seat       FlightSeatAssignment
+	Assignment FlightAssignment
+	Redemption *VoucherRedemption
+}
+
+// State reports a used voucher as redeemed even if its seat was revoked later.
+func (v IssuedVoucher) State() VoucherState {
+	switch {
+	case v.Redemption != nil:
+		return VoucherRedeemed
+	case v.Seat.DeletedAt.Valid || v.Assignment.DeletedAt.Valid:
+		return VoucherRevoked
+	default:
+		return VoucherIssued
+	}
+}
+
+// VoucherKey is a public key that voucher tokens are verified with, named by
+// the key ID carried in the token header.
+type VoucherKey struct {
+	ID        string
+	PublicKey ed25519.PublicKey
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrConflict is returned when a write breaks a uniqueness constraint, usually
+// because a concurrent request stored the same row first.
+var ErrConflict = errors.New("conflicting write")
@@ -3,18 +3,36 @@ package repository
 import (
 	"bookcabin-voucher/internal/dto"
 	"bookcabin-voucher/internal/model"
-	"gorm.io/gorm"
+	"context"
 )
 
 type FlightRepository interface {
-	BeginTx() *gorm.DB
+	// WithinTx runs fn with a repository bound to a single transaction. The
+	// transaction is committed when fn returns nil and rolled back when it
+	// returns an error or panics.
+	WithinTx(ctx context.Context, fn func(repo FlightRepository) error) error
+	// LockFlight serialises writers of a flight's assignment. Call it first
+	// inside WithinTx; the lock is held until the transaction ends.
+	LockFlight(ctx context.Context, flightNumber, date string) error
 
-	CountByFlightAndDate(flightNumber, date string) int64
-	CountByFlightAndDateTx(tx *gorm.DB, flightNumber, date string) int64
-	GetByFilter(filter dto.FlightFilter) ([]model.FlightAssignment, error)
-	GetByFilterTx(tx *gorm.DB, filter dto.FlightFilter) ([]model.FlightAssignment, error)
+	CountByFlightAndDate(ctx context.Context, flightNumber, date string) int64
+	GetByFilter(ctx context.Context, filter dto.FlightFilter) ([]model.FlightAssignment, error)
+	ListAssignments(ctx context.Context, filter dto.AssignmentFilter) ([]model.FlightAssignment, error)
 
-	CreateTx(tx *gorm.DB, assignment *model.FlightAssignment) (*model.FlightAssignment, error)
-	DeleteSeatsByFilterTx(tx *gorm.DB, filter dto.FlightFilter) (int64, error)
-	BulkCreateSeatAssignmentsTx(tx *gorm.DB, seats []model.FlightSeatAssignment) error
+	Create(ctx context.Context, assignment *model.FlightAssignment) (*model.FlightAssignment, error)
+	DeleteSeatsByFilter(ctx context.Context, filter dto.FlightFilter, actor string) (int64, error)
+	BulkCreateSeatAssignments(ctx context.Context, seats []model.FlightSeatAssignment) error
+	RevokeAssignment(ctx context.Context, assignmentID uint, actor, reason string) error
+	RevokeSeats(ctx context.Context, assignmentID uint, seats []string, actor, reason string) error
+
+	AppendEvent(ctx context.Context, event *model.FlightAssignmentEvent) error
+	ListEvents(ctx context.Context, flightNumber, date string) ([]model.FlightAssignmentEvent, error)
+
+	// FindVoucher loads the seat issued with code, revoked or not, or returns nil when no seat has it.
+	FindVoucher(ctx context.Context, code string) (*model.IssuedVoucher, error)
+	RedeemVoucher(ctx context.Context, redemption *model.VoucherRedemption) error
+
+	GetSeatInventory(ctx context.Context, flightNumber, date string) ([]model.FlightSeatInventory, error)
+	FindOccupiedSeats(ctx context.Context, flightNumber, date string) ([]string, error)
+	SetSeatStatus(ctx context.Context, flightNumber, date string, seats []string, status model.SeatStatus) error
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"bookcabin-voucher/internal/model"
+	"context"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims record.Key for a new request. When the key is already
+	// held by an unexpired record, that record is returned instead.
+	Reserve(ctx context.Context, record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error)
+	// Complete stores the response of the request holding key.
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	// Release drops a reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
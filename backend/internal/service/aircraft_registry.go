@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"sort"
+	"strings"
+)
+
+type aircraftCatalog struct {
+	names    map[string]int // lower-cased name or alias -> index into aircraft
+	aircraft []model.AircraftInfo
+}
+
+// NewAircraftCatalog builds an AircraftRegistry from the layout keys and their aliases.
+func NewAircraftCatalog(layouts map[model.AircraftType]model.AircraftLayout) AircraftRegistry {
+	c := &aircraftCatalog{
+		names:    make(map[string]int),
+		aircraft: make([]model.AircraftInfo, 0, len(layouts)),
+	}
+	for aircraft, layout := range layouts {
+		c.aircraft = append(c.aircraft, model.AircraftInfo{
+			Type:            aircraft,
+			Aliases:         layout.Aliases,
+			Seats:           len(layout.SeatMap()),
+			DefaultVouchers: layout.DefaultVouchers,
+			MaxVouchers:     layout.MaxVouchers,
+		})
+	}
+	sort.Slice(c.aircraft, func(i, j int) bool { return c.aircraft[i].Type < c.aircraft[j].Type })
+
+	for i, info := range c.aircraft {
+		c.names[strings.ToLower(string(info.Type))] = i
+		for _, alias := range info.Aliases {
+			c.names[strings.ToLower(alias)] = i
+		}
+	}
+	return c
+}
+
+func (c *aircraftCatalog) Resolve(name string) (model.AircraftInfo, bool) {
+	i, ok := c.names[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return model.AircraftInfo{}, false
+	}
+	return c.aircraft[i], true
+}
+
+func (c *aircraftCatalog) Aircraft() []model.AircraftInfo {
+	return c.aircraft
+}
+
+var _ AircraftRegistry = (*aircraftCatalog)(nil)
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	bcbpFlightRegex = regexp.MustCompile(`^([A-Z0-9]{2})(\d{1,4})$`)
+	bcbpSeatRegex   = regexp.MustCompile(`^(\d{1,3})([A-Z])$`)
+)
+
+// EncodeBCBP writes a voucher as a single leg IATA Bar Coded Boarding Pass
+// (Resolution 792, format M) so gate scanners read it like a boarding pass.
+// Routes are not stored with vouchers, so from and to are the airport codes
+// given by the caller, blank when unknown. The crew ID fills the booking
+// reference and the voucher code the airline individual use field.
+func EncodeBCBP(voucher model.Voucher, from, to string) (string, error) {
+	flightDate, err := time.Parse("02-01-06", voucher.FlightDate)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode boarding pass: %w", err)
+	}
+	flight := bcbpFlightRegex.FindStringSubmatch(voucher.FlightNumber)
+	if flight == nil {
+		return "", fmt.Errorf("failed to encode boarding pass: flight number %q", voucher.FlightNumber)
+	}
+	number, _ := strconv.Atoi(flight[2])
+	seat := bcbpSeatRegex.FindStringSubmatch(voucher.Seat)
+	if seat == nil {
+		return "", fmt.Errorf("failed to encode boarding pass: seat %q", voucher.Seat)
+	}
+	row, _ := strconv.Atoi(seat[1])
+	airlineData := strings.ReplaceAll(voucher.Code, "-", "")
+
+	var pass strings.Builder
+	pass.WriteString("M1")                                        // format code, number of legs
+	fmt.Fprintf(&pass, "%-20.20s", bcbpName(voucher.CrewName))    // passenger name
+	pass.WriteString("E")                                         // electronic ticket indicator
+	fmt.Fprintf(&pass, "%-7.7s", strings.ToUpper(voucher.CrewID)) // booking reference
+	fmt.Fprintf(&pass, "%-3.3s%-3.3s", from, to)                  // from and to airports
+	fmt.Fprintf(&pass, "%-3s", flight[1])                         // operating carrier
+	fmt.Fprintf(&pass, "%04d ", number)                           // flight number
+	fmt.Fprintf(&pass, "%03d", flightDate.YearDay())              // julian date of flight
+	pass.WriteString("Y")                                         // compartment
+	fmt.Fprintf(&pass, "%03d%s", row, seat[2])                    // seat
+	pass.WriteString("0000 ")                                     // check-in sequence number
+	pass.WriteString("1")                                         // passenger status
+	fmt.Fprintf(&pass, "%02X%s", len(airlineData), airlineData)   // variable size field
+	return pass.String(), nil
+}
+
+// bcbpName writes a crew name as SURNAME/GIVEN NAMES in the letters a boarding
+// pass allows, e.g. "Ann Marie Lee" as "LEE/ANN MARIE".
+func bcbpName(name string) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToUpper(name)) {
+		word = strings.Map(func(r rune) rune {
+			if r < 'A' || r > 'Z' {
+				return -1
+			}
+			return r
+		}, word)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	last := len(words) - 1
+	return words[last] + "/" + strings.Join(words[:last], " ")
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEncodeBCBP(t *testing.T) {
+	voucher := model.Voucher{
+		Code: "R7X6-5JZ3-03J1", CrewName: "Ann Marie Lee", CrewID: "98123",
+		FlightNumber: "JT692", FlightDate: "26-07-25", Seat: "14D",
+	}
+
+	pass, err := EncodeBCBP(voucher, "CGK", "DPS")
+	require.NoError(t, err)
+
+	// the 60 mandatory characters of the leg, then the voucher code
+	assert.Len(t, pass, 60+12)
+	assert.Equal(t, "M1", pass[0:2])
+	assert.Equal(t, "LEE/ANN MARIE       ", pass[2:22])
+	assert.Equal(t, "E", pass[22:23])
+	assert.Equal(t, "98123  ", pass[23:30])
+	assert.Equal(t, "CGK", pass[30:33])
+	assert.Equal(t, "DPS", pass[33:36])
+	assert.Equal(t, "JT ", pass[36:39])
+	assert.Equal(t, "0692 ", pass[39:44])
+	assert.Equal(t, "207", pass[44:47]) // 26 July
+	assert.Equal(t, "Y", pass[47:48])
+	assert.Equal(t, "014D", pass[48:52])
+	assert.Equal(t, "0000 ", pass[52:57])
+	assert.Equal(t, "1", pass[57:58])
+	assert.Equal(t, "0C", pass[58:60])
+	assert.Equal(t, "R7X65JZ303J1", pass[60:])
+}
+
+func TestEncodeBCBP_Defaults(t *testing.T) {
+	voucher := model.Voucher{CrewName: "Zoë O'Brien-Arki", CrewID: "98123456", FlightNumber: "JT5", FlightDate: "01-01-26", Seat: "3B"}
+
+	pass, err := EncodeBCBP(voucher, "", "")
+	require.NoError(t, err)
+
+	assert.Len(t, pass, 60)
+	assert.Equal(t, "OBRIENARKI/ZO       ", pass[2:22])
+	assert.Equal(t, "9812345", pass[23:30])
+	assert.Equal(t, "      ", pass[30:36])
+	assert.Equal(t, "0005 ", pass[39:44])
+	assert.Equal(t, "001", pass[44:47])
+	assert.Equal(t, "003B", pass[48:52])
+	assert.Equal(t, "00", pass[58:60]) // seats issued before voucher codes
+}
+
+func TestEncodeBCBP_Invalid(t *testing.T) {
+	valid := model.Voucher{CrewName: "Ann Lee", FlightNumber: "JT692", FlightDate: "26-07-25", Seat: "3B"}
+
+	for name, change := range map[string]func(v *model.Voucher){
+		"date":          func(v *model.Voucher) { v.FlightDate = "2025-07-26" },
+		"flight number": func(v *model.Voucher) { v.FlightNumber = "JT69212" },
+		"seat":          func(v *model.Voucher) { v.Seat = "1234A" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			voucher := valid
+			change(&voucher)
+			_, err := EncodeBCBP(voucher, "", "")
+			assert.ErrorContains(t, err, "failed to encode boarding pass")
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LayoutError describes one problem found in a layout file.
+type LayoutError struct {
+	Aircraft model.AircraftType // empty when the problem concerns the whole file
+	Field    string             // JSON path inside the aircraft entry, e.g. "sections[1].startRow"
+	Reason   string
+}
+
+func (e LayoutError) Error() string {
+	switch {
+	case e.Aircraft == "":
+		return e.Reason
+	case e.Field == "":
+		return fmt.Sprintf("%s: %s", e.Aircraft, e.Reason)
+	default:
+		return fmt.Sprintf("%s: %s: %s", e.Aircraft, e.Field, e.Reason)
+	}
+}
+
+// LayoutErrors collects every problem in a layout file so they can be fixed in one go.
+type LayoutErrors []LayoutError
+
+func (e LayoutErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return "invalid seat layout: " + strings.Join(msgs, "; ")
+}
+
+var (
+	seatLetterRegex   = regexp.MustCompile(`^[A-Z]$`)
+	seatCodeRegex     = regexp.MustCompile(`^\d+[A-Z]$`)
+	unknownFieldRegex = regexp.MustCompile(`unknown field "([^"]+)"`)
+)
+
+// LoadLayouts reads, decodes and validates the layout file at path.
+// Semantic problems are returned as LayoutErrors.
+func LoadLayouts(path string) (map[model.AircraftType]model.AircraftLayout, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seat layout file: %w", err)
+	}
+	return ParseLayouts(file)
+}
+
+// ParseLayouts decodes a layout document, rejecting unknown keys, and validates it.
+func ParseLayouts(data []byte) (map[model.AircraftType]model.AircraftLayout, error) {
+	var raw map[model.AircraftType]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON in seat layout file: %w", err)
+	}
+
+	var errs LayoutErrors
+	layouts := make(map[model.AircraftType]model.AircraftLayout, len(raw))
+	for _, aircraft := range sortedKeys(raw) {
+		decoder := json.NewDecoder(bytes.NewReader(raw[aircraft]))
+		decoder.DisallowUnknownFields()
+
+		var layout model.AircraftLayout
+		if err := decoder.Decode(&layout); err != nil {
+			errs = append(errs, decodeError(aircraft, err))
+			continue
+		}
+		layouts[aircraft] = layout
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if err := ValidateLayouts(layouts); err != nil {
+		return nil, err
+	}
+	return layouts, nil
+}
+
+func decodeError(aircraft model.AircraftType, err error) LayoutError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return LayoutError{Aircraft: aircraft, Field: typeErr.Field, Reason: "must be " + typeErr.Type.String()}
+	}
+	if m := unknownFieldRegex.FindStringSubmatch(err.Error()); m != nil {
+		return LayoutError{Aircraft: aircraft, Field: m[1], Reason: "unknown field"}
+	}
+	return LayoutError{Aircraft: aircraft, Reason: err.Error()}
+}
+
+// ValidateLayouts checks every layout and the aliases across layouts, returning
+// LayoutErrors listing all problems, or nil when the layouts are usable.
+func ValidateLayouts(layouts map[model.AircraftType]model.AircraftLayout) error {
+	if len(layouts) == 0 {
+		return LayoutErrors{{Reason: "layout file defines no aircraft"}}
+	}
+
+	var errs LayoutErrors
+	names := make(map[string]model.AircraftType, len(layouts))
+	for aircraft := range layouts {
+		names[strings.ToLower(string(aircraft))] = aircraft
+	}
+	for _, aircraft := range sortedKeys(layouts) {
+		layout := layouts[aircraft]
+		errs = append(errs, validateLayout(aircraft, layout)...)
+
+		for i, alias := range layout.Aliases {
+			field := fmt.Sprintf("aliases[%d]", i)
+			key := strings.ToLower(strings.TrimSpace(alias))
+			if key == "" {
+				errs = append(errs, LayoutError{aircraft, field, "must not be empty"})
+				continue
+			}
+			if owner, taken := names[key]; taken && owner != aircraft {
+				errs = append(errs, LayoutError{aircraft, field, fmt.Sprintf("%q is already used by %s", alias, owner)})
+				continue
+			}
+			names[key] = aircraft
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateLayout(aircraft model.AircraftType, l model.AircraftLayout) LayoutErrors {
+	var errs LayoutErrors
+	add := func(field, reason string, args ...any) {
+		errs = append(errs, LayoutError{aircraft, field, fmt.Sprintf(reason, args...)})
+	}
+	inRange := func(row int) bool { return row >= l.StartRow && row <= l.EndRow }
+
+	if strings.TrimSpace(string(aircraft)) == "" {
+		add("", "aircraft name must not be empty")
+	}
+	if l.StartRow < 1 {
+		add("startRow", "must be at least 1")
+	}
+	if l.EndRow < l.StartRow {
+		add("endRow", "must not be before startRow %d", l.StartRow)
+	}
+	if len(l.Seats) == 0 {
+		add("seats", "must list at least one seat letter")
+	}
+	if l.DefaultVouchers < 0 {
+		add("defaultVouchers", "must not be negative")
+	}
+	if l.MaxVouchers < 0 {
+		add("maxVouchers", "must not be negative")
+	}
+	if l.MaxVouchers > 0 && l.DefaultVouchers > l.MaxVouchers {
+		add("defaultVouchers", "must not exceed maxVouchers %d", l.MaxVouchers)
+	}
+	errs = append(errs, validateLetters(aircraft, "seats", l.Seats)...)
+
+	for i, row := range l.SkippedRows {
+		if !inRange(row) {
+			add(fmt.Sprintf("skippedRows[%d]", i), "row %d is outside %d-%d", row, l.StartRow, l.EndRow)
+		}
+	}
+	for i, row := range l.ExitRows {
+		if !inRange(row) {
+			add(fmt.Sprintf("exitRows[%d]", i), "row %d is outside %d-%d", row, l.StartRow, l.EndRow)
+		}
+	}
+	rows := make([]int, 0, len(l.RowSeats))
+	for row := range l.RowSeats {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	for _, row := range rows {
+		letters := l.RowSeats[row]
+		field := fmt.Sprintf("rowSeats.%d", row)
+		if !inRange(row) {
+			add(field, "row %d is outside %d-%d", row, l.StartRow, l.EndRow)
+		}
+		if len(letters) == 0 {
+			add(field, "must list at least one seat letter")
+		}
+		errs = append(errs, validateLetters(aircraft, field, letters)...)
+	}
+
+	sections := append([]model.CabinSection(nil), l.Sections...)
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].StartRow < sections[j].StartRow })
+	for i, s := range l.Sections {
+		field := fmt.Sprintf("sections[%d]", i)
+		if strings.TrimSpace(s.Name) == "" {
+			add(field+".name", "must not be empty")
+		}
+		if s.EndRow < s.StartRow {
+			add(field+".endRow", "must not be before startRow %d", s.StartRow)
+		}
+		if !inRange(s.StartRow) || !inRange(s.EndRow) {
+			add(field, "rows %d-%d are outside %d-%d", s.StartRow, s.EndRow, l.StartRow, l.EndRow)
+		}
+		errs = append(errs, validateLetters(aircraft, field+".seats", s.Seats)...)
+	}
+	for i := 1; i < len(sections); i++ {
+		if sections[i].StartRow <= sections[i-1].EndRow {
+			add("sections", "%q overlaps %q", sections[i].Name, sections[i-1].Name)
+		}
+	}
+
+	// the checks below resolve seats, which only makes sense on a sane grid
+	if len(errs) > 0 {
+		return errs
+	}
+
+	existing := make(map[string]bool)
+	letters := make(map[string]bool)
+	unblocked := l
+	unblocked.BlockedSeats = nil
+	for _, seat := range unblocked.SeatMap() {
+		existing[seat.Code()] = true
+		letters[seat.Letter] = true
+	}
+	for i, code := range l.BlockedSeats {
+		if !seatCodeRegex.MatchString(code) {
+			add(fmt.Sprintf("blockedSeats[%d]", i), "%q is not a seat like 12C", code)
+		} else if !existing[code] {
+			add(fmt.Sprintf("blockedSeats[%d]", i), "seat %s does not exist", code)
+		}
+	}
+	for i, letter := range l.AisleAfter {
+		if !letters[letter] {
+			add(fmt.Sprintf("aisleAfter[%d]", i), "seat letter %q is not used in any row", letter)
+		}
+	}
+	if issuable := len(l.SeatMap()); len(errs) == 0 && issuable == 0 {
+		add("", "has no issuable seats")
+	} else if l.MaxVouchers > issuable {
+		add("maxVouchers", "exceeds the %d issuable seats", issuable)
+	}
+	return errs
+}
+
+func validateLetters(aircraft model.AircraftType, field string, letters []string) LayoutErrors {
+	var errs LayoutErrors
+	seen := make(map[string]bool, len(letters))
+	for i, letter := range letters {
+		path := field + "[" + strconv.Itoa(i) + "]"
+		if !seatLetterRegex.MatchString(letter) {
+			errs = append(errs, LayoutError{aircraft, path, fmt.Sprintf("%q is not a single capital letter", letter)})
+		} else if seen[letter] {
+			errs = append(errs, LayoutError{aircraft, path, fmt.Sprintf("duplicate seat letter %q", letter)})
+		}
+		seen[letter] = true
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[model.AircraftType]V) []model.AircraftType {
+	keys := make([]model.AircraftType, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bookcabin-voucher/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoadLayouts_ShippedFileIsValid(t *testing.T) {
+	layouts, err := LoadLayouts(config.LoadConfig().SeatLayoutPath)
+
+	require.NoError(t, err)
+	assert.Len(t, layouts, 3)
+}
+
+func TestParseLayouts_ReportsEveryProblem(t *testing.T) {
+	_, err := ParseLayouts([]byte(`{
+		"ATR": {"startRow": 10, "endRow": 2, "seats": [], "defaultVouchers": 4, "maxVouchers": 3},
+		"Airbus 320": {
+			"aliases": ["ATR"],
+			"startRow": 1, "endRow": 5,
+			"seats": ["A", "B", "B", "cc"],
+			"exitRows": [9],
+			"sections": [
+				{"name": "business", "startRow": 1, "endRow": 3},
+				{"name": "economy", "startRow": 3, "endRow": 5}
+			]
+		},
+		"Boeing 737 Max": {
+			"startRow": 1, "endRow": 5,
+			"seats": ["A", "B"],
+			"blockedSeats": ["9A", "A1"],
+			"aisleAfter": ["C"]
+		}
+	}`))
+
+	var layoutErrs LayoutErrors
+	require.ErrorAs(t, err, &layoutErrs)
+	assert.ElementsMatch(t, LayoutErrors{
+		{"ATR", "endRow", "must not be before startRow 10"},
+		{"ATR", "seats", "must list at least one seat letter"},
+		{"ATR", "defaultVouchers", "must not exceed maxVouchers 3"},
+		{"Airbus 320", "seats[2]", `duplicate seat letter "B"`},
+		{"Airbus 320", "seats[3]", `"cc" is not a single capital letter`},
+		{"Airbus 320", "exitRows[0]", "row 9 is outside 1-5"},
+		{"Airbus 320", "sections", `"economy" overlaps "business"`},
+		{"Airbus 320", "aliases[0]", `"ATR" is already used by ATR`},
+		{"Boeing 737 Max", "blockedSeats[0]", "seat 9A does not exist"},
+		{"Boeing 737 Max", "blockedSeats[1]", `"A1" is not a seat like 12C`},
+		{"Boeing 737 Max", "aisleAfter[0]", `seat letter "C" is not used in any row`},
+	}, layoutErrs)
+}
+
+func TestParseLayouts_UnknownField(t *testing.T) {
+	_, err := ParseLayouts([]byte(`{"ATR": {"startRow": 1, "endRow": 5, "seats": ["A"], "exitRow": [2]}}`))
+
+	var layoutErrs LayoutErrors
+	require.ErrorAs(t, err, &layoutErrs)
+	assert.Equal(t, LayoutErrors{{"ATR", "exitRow", "unknown field"}}, layoutErrs)
+}
+
+func TestParseLayouts_WrongType(t *testing.T) {
+	_, err := ParseLayouts([]byte(`{"ATR": {"startRow": "1", "endRow": 5, "seats": ["A"]}}`))
+
+	var layoutErrs LayoutErrors
+	require.ErrorAs(t, err, &layoutErrs)
+	assert.Equal(t, LayoutErrors{{"ATR", "startRow", "must be int"}}, layoutErrs)
+}
+
+func TestParseLayouts_InvalidJSON(t *testing.T) {
+	_, err := ParseLayouts([]byte(`{"ATR": `))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JSON")
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+// reloadDebounce collapses the burst of events editors emit for a single save.
+const reloadDebounce = 250 * time.Millisecond
+
+// Watch reloads the layout file whenever it changes on disk until ctx is done.
+// The parent directory is watched so that editors replacing the file by rename
+// are picked up too. Failed reloads are logged and the old layouts are kept.
+func (s *SeatGenerator) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create layout watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(s.path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", s.path, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(s.path) {
+					continue
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+					debounce = time.After(reloadDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[SeatLayout] Watcher error: %v", err)
+			case <-debounce:
+				debounce = nil
+				s.ReloadAndLog("file change")
+			}
+		}
+	}()
+	return nil
+}
+
+// ReloadAndLog reloads the layouts and logs the outcome, naming what triggered it.
+func (s *SeatGenerator) ReloadAndLog(trigger string) {
+	if err := s.Reload(); err != nil {
+		log.Printf("[SeatLayout] Reload on %s rejected, keeping previous layouts: %v", trigger, err)
+		return
+	}
+	log.Printf("[SeatLayout] Reloaded %s on %s", s.path, trigger)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	smallLayout = `{"Small": {"startRow": 1, "endRow": 2, "seats": ["A", "B"]}}`
+	largeLayout = `{"Small": {"startRow": 1, "endRow": 20, "seats": ["A", "B"]}, "Large": {"startRow": 1, "endRow": 40, "seats": ["A", "B", "C"]}}`
+)
+
+func writeLayout(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestReload_SwapsValidLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.json")
+	writeLayout(t, path, smallLayout)
+	gen, err := NewSeatAllocator(path, nil, nil)
+	require.NoError(t, err)
+
+	_, ok := gen.Resolve("Large")
+	assert.False(t, ok)
+
+	writeLayout(t, path, largeLayout)
+	require.NoError(t, gen.Reload())
+
+	_, ok = gen.Resolve("Large")
+	assert.True(t, ok)
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 10, make([]string, 0), "")
+	assert.NoError(t, err)
+	assert.Len(t, seats, 10)
+}
+
+func TestReload_RejectsBadLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.json")
+	writeLayout(t, path, smallLayout)
+	gen, err := NewSeatAllocator(path, nil, nil)
+	require.NoError(t, err)
+
+	writeLayout(t, path, `{"Small": {"startRow": 1, "endRow": 2`)
+	assert.Error(t, gen.Reload())
+
+	writeLayout(t, path, `{"Small": {"startRow": 5, "endRow": 2, "seats": ["A"]}}`)
+	assert.Error(t, gen.Reload())
+
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 4, make([]string, 0), "")
+	assert.NoError(t, err)
+	assert.Len(t, seats, 4)
+}
+
+func TestReload_ConcurrentWithGenerateSeats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.json")
+	writeLayout(t, path, smallLayout)
+	gen, err := NewSeatAllocator(path, nil, nil)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				seats, err := gen.GenerateSeats(t.Context(), "Small", 4, make([]string, 0), "")
+				assert.NoError(t, err)
+				assert.Len(t, seats, 4)
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			writeLayout(t, path, largeLayout)
+		} else {
+			writeLayout(t, path, smallLayout)
+		}
+		_ = gen.Reload() // a half-written file is rejected, which is fine here
+	}
+	wg.Wait()
+}
+
+func TestWatch_ReloadsOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.json")
+	writeLayout(t, path, smallLayout)
+	gen, err := NewSeatAllocator(path, nil, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, gen.Watch(ctx))
+
+	writeLayout(t, path, largeLayout)
+
+	assert.Eventually(t, func() bool {
+		_, ok := gen.Resolve("Large")
+		return ok
+	}, 5*time.Second, 50*time.Millisecond)
+}
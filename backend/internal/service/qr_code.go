@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// QR code image formats.
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+const qrQuietZone = 4 // modules of white border scanners need around the code
+
+// QRCode is the module grid of an encoded QR code.
+type QRCode struct {
+	code barcode.Barcode
+}
+
+// EncodeQR encodes content as a QR code at error correction level M, which
+// keeps a printed or on-screen code readable when slightly damaged.
+func EncodeQR(content string) (*QRCode, error) {
+	if content == "" {
+		return nil, fmt.Errorf("failed to encode QR code: no content")
+	}
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return &QRCode{code: code}, nil
+}
+
+// Modules returns the width of the code in modules, quiet zone included.
+func (q *QRCode) Modules() int {
+	return q.code.Bounds().Dx() + 2*qrQuietZone
+}
+
+// Image draws the code with the quiet zone, each module scale pixels square.
+func (q *QRCode) Image(scale int) image.Image {
+	size := q.Modules() * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	q.eachDark(func(x, y int) {
+		for dy := 0; dy < scale; dy++ {
+			for dx := 0; dx < scale; dx++ {
+				img.SetGray(x*scale+dx, y*scale+dy, color.Gray{})
+			}
+		}
+	})
+	return img
+}
+
+// Write draws the code as a PNG or an SVG about size pixels wide, never
+// smaller than one pixel per module.
+func (q *QRCode) Write(w io.Writer, format string, size int) error {
+	scale := max(size/q.Modules(), 1)
+	switch format {
+	case QRFormatPNG:
+		return png.Encode(w, q.Image(scale))
+	case QRFormatSVG:
+		return q.writeSVG(w, scale)
+	default:
+		return fmt.Errorf("unsupported QR code format %q", format)
+	}
+}
+
+// writeSVG draws every dark module as a unit square of one path, scaled by the
+// viewBox so the code stays sharp at any size.
+func (q *QRCode) writeSVG(w io.Writer, scale int) error {
+	var path strings.Builder
+	q.eachDark(func(x, y int) {
+		fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+	})
+	modules := q.Modules()
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="%s"/></svg>`,
+		modules*scale, modules*scale, modules, modules, path.String())
+	return err
+}
+
+// eachDark calls fn with the position of every dark module, quiet zone included.
+func (q *QRCode) eachDark(fn func(x, y int)) {
+	bounds := q.code.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray := color.GrayModel.Convert(q.code.At(x, y)).(color.Gray); gray.Y < 0x80 {
+				fn(x-bounds.Min.X+qrQuietZone, y-bounds.Min.Y+qrQuietZone)
+			}
+		}
+	}
+}
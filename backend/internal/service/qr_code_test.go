@@ -0,0 +1,58 @@
+package service
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestEncodeQR_PNG(t *testing.T) {
+	code, err := EncodeQR("R7X6-5JZ3-03J1")
+	require.NoError(t, err)
+	assert.Equal(t, 21+2*qrQuietZone, code.Modules()) // version 1
+
+	var out bytes.Buffer
+	require.NoError(t, code.Write(&out, QRFormatPNG, 256))
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+
+	scale := 256 / code.Modules()
+	assert.Equal(t, code.Modules()*scale, img.Bounds().Dx())
+	assert.Equal(t, img.Bounds().Dx(), img.Bounds().Dy())
+
+	gray := func(x, y int) uint8 { return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y }
+	// white quiet zone, then the dark corner of the top-left finder pattern
+	assert.Equal(t, uint8(0xff), gray(0, 0))
+	assert.Equal(t, uint8(0xff), gray(qrQuietZone*scale-1, qrQuietZone*scale-1))
+	assert.Equal(t, uint8(0x00), gray(qrQuietZone*scale, qrQuietZone*scale))
+}
+
+func TestEncodeQR_SVG(t *testing.T) {
+	code, err := EncodeQR("R7X6-5JZ3-03J1")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, code.Write(&out, QRFormatSVG, 10))
+	svg := out.String()
+
+	// never smaller than a pixel per module
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="29" height="29" viewBox="0 0 29 29"`), svg)
+	assert.Contains(t, svg, "M4 4h1v1h-1z") // the finder pattern corner
+	assert.True(t, strings.HasSuffix(svg, "</svg>"))
+}
+
+func TestEncodeQR_Invalid(t *testing.T) {
+	_, err := EncodeQR("")
+	assert.Error(t, err)
+
+	_, err = EncodeQR(strings.Repeat("x", 4000)) // more than a version 40 code holds
+	assert.Error(t, err)
+
+	code, err := EncodeQR("x")
+	require.NoError(t, err)
+	assert.Error(t, code.Write(&bytes.Buffer{}, "gif", 100))
+}
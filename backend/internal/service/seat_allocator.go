@@ -1,7 +1,32 @@
 package service
 
-import "bookcabin-voucher/internal/model"
+import (
+	"bookcabin-voucher/internal/model"
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnknownAircraft is returned for an aircraft without a seat layout.
+	ErrUnknownAircraft = errors.New("unknown aircraft")
+	// ErrUnknownStrategy is returned for a seat strategy that is not registered.
+	ErrUnknownStrategy = errors.New("unknown seat strategy")
+	// ErrNotEnoughSeats is returned when the free seats cannot satisfy a request.
+	ErrNotEnoughSeats = errors.New("not enough available seats")
+)
+
+// AircraftRegistry knows which aircraft types have a seat layout.
+type AircraftRegistry interface {
+	// Resolve looks up an aircraft by name or alias, case-insensitively.
+	Resolve(name string) (model.AircraftInfo, bool)
+	// Aircraft lists the supported aircraft types ordered by name.
+	Aircraft() []model.AircraftInfo
+}
 
 type SeatAllocator interface {
-	GenerateSeats(aircraft model.AircraftType, count int, existingSeats []string) ([]string, error)
+	AircraftRegistry
+
+	// GenerateSeats picks count free seats using the named strategy. An empty
+	// strategy falls back to the aircraft's configured default, then to random.
+	GenerateSeats(ctx context.Context, aircraft model.AircraftType, count int, existingSeats []string, strategy string) ([]string, error)
 }
@@ -2,53 +2,146 @@ package service
 
 import (
 	"bookcabin-voucher/internal/model"
-	"encoding/json"
+	"context"
 	"fmt"
 	"math/rand"
-	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type SeatGenerator struct {
-	layouts map[model.AircraftType]model.AircraftLayout
+	path       string
+	current    atomic.Pointer[layoutSet]
+	strategies map[model.AircraftType]string // default strategy per aircraft
+
+	mu  sync.Mutex // guards rng, *rand.Rand is not safe for concurrent use
+	rng *rand.Rand
+}
+
+// layoutSet is an immutable snapshot of the loaded layouts. Reload swaps the
+// whole set at once so a request never sees layouts from two different files.
+type layoutSet struct {
+	AircraftRegistry
+	seatMaps map[model.AircraftType][]model.Seat
 }
 
-func NewSeatAllocator(path string) *SeatGenerator {
-	file, err := os.ReadFile(path)
+// NewSeatAllocator loads the layouts at path and draws seats from src.
+// Pass a seeded source to make allocations reproducible, or nil to seed from the clock.
+// defaultStrategies names the strategy used per aircraft when a request does not pick one;
+// its keys may be any name or alias of an aircraft in the layouts.
+func NewSeatAllocator(path string, src rand.Source, defaultStrategies map[string]string) (*SeatGenerator, error) {
+	layouts, err := LoadLayouts(path)
 	if err != nil {
-		panic("Failed to read seat layout file: " + err.Error())
+		return nil, err
+	}
+	gen := newSeatGenerator(layouts, src)
+	gen.path = path
+	gen.strategies = make(map[model.AircraftType]string, len(defaultStrategies))
+	for aircraft, name := range defaultStrategies {
+		info, ok := gen.Resolve(aircraft)
+		if !ok {
+			return nil, fmt.Errorf("unknown aircraft %q for default seat strategy %q", aircraft, name)
+		}
+		if _, ok := LookupStrategy(name); !ok {
+			return nil, fmt.Errorf("unknown default seat strategy %q for %s", name, aircraft)
+		}
+		gen.strategies[info.Type] = name
 	}
-	var layouts map[model.AircraftType]model.AircraftLayout
-	if err := json.Unmarshal(file, &layouts); err != nil {
-		panic("Invalid JSON in seat layout file: " + err.Error())
+	return gen, nil
+}
+
+func newSeatGenerator(layouts map[model.AircraftType]model.AircraftLayout, src rand.Source) *SeatGenerator {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
 	}
-	return &SeatGenerator{layouts: layouts}
+	gen := &SeatGenerator{rng: rand.New(src)}
+	gen.current.Store(newLayoutSet(layouts))
+	return gen
+}
+
+func newLayoutSet(layouts map[model.AircraftType]model.AircraftLayout) *layoutSet {
+	seatMaps := make(map[model.AircraftType][]model.Seat, len(layouts))
+	for aircraft, layout := range layouts {
+		seatMaps[aircraft] = layout.SeatMap()
+	}
+	return &layoutSet{AircraftRegistry: NewAircraftCatalog(layouts), seatMaps: seatMaps}
+}
+
+// Reload re-reads the layout file and swaps it in only if it loads cleanly.
+// On error the previous layouts stay in use. Safe to call concurrently with GenerateSeats.
+func (s *SeatGenerator) Reload() error {
+	layouts, err := LoadLayouts(s.path)
+	if err != nil {
+		return err
+	}
+	s.current.Store(newLayoutSet(layouts))
+	return nil
+}
+
+func (s *SeatGenerator) Resolve(name string) (model.AircraftInfo, bool) {
+	return s.current.Load().Resolve(name)
+}
+
+func (s *SeatGenerator) Aircraft() []model.AircraftInfo {
+	return s.current.Load().Aircraft()
 }
 
-func (s *SeatGenerator) GenerateSeats(aircraft model.AircraftType, count int, existingSeats []string) ([]string, error) {
-	layout, ok := s.layouts[aircraft]
+// GenerateSeats picks count seats from every seat of the aircraft not listed
+// in existingSeats. It only fails when the free set is too small for the
+// strategy or the aircraft or strategy is unknown.
+func (s *SeatGenerator) GenerateSeats(ctx context.Context, aircraft model.AircraftType, count int, existingSeats []string, strategy string) ([]string, error) {
+	seatMap, ok := s.current.Load().seatMaps[aircraft]
 	if !ok {
-		return nil, fmt.Errorf("unknown aircraft")
+		return nil, ErrUnknownAircraft
 	}
+	if strategy == "" {
+		strategy = s.strategies[aircraft]
+	}
+	if strategy == "" {
+		strategy = StrategyRandom
+	}
+	picker, ok := LookupStrategy(strategy)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownStrategy, strategy)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	free := freeSeats(seatMap, existingSeats)
+	if count > len(free) {
+		return nil, ErrNotEnoughSeats
+	}
+
+	s.mu.Lock()
+	picked, err := picker.Pick(free, count, s.rng)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]string, 0, count)
-	seen := make(map[string]bool)
+	for _, seat := range picked {
+		result = append(result, seat.Code())
+	}
+	return result, nil
+}
+
+// freeSeats returns the seats of seatMap not taken by existingSeats, in seat map order.
+func freeSeats(seatMap []model.Seat, existingSeats []string) []model.Seat {
+	taken := make(map[string]bool, len(existingSeats))
 	for _, seat := range existingSeats {
-		seen[seat] = true
-	}
-	tries := 0
-	maxTries := 1000
-	for len(result) < count && tries < maxTries {
-		row := rand.Intn(layout.EndRow-layout.StartRow+1) + layout.StartRow
-		seat := fmt.Sprintf("%d%s", row, layout.Seats[rand.Intn(len(layout.Seats))])
-		if !seen[seat] {
-			result = append(result, seat)
-			seen[seat] = true
-		}
-		tries++
+		taken[seat] = true
 	}
-	if len(result) < count {
-		return nil, fmt.Errorf("not enough available seats")
+	free := make([]model.Seat, 0, len(seatMap))
+	for _, seat := range seatMap {
+		if !taken[seat.Code()] {
+			free = append(free, seat)
+		}
 	}
-	return result, nil
+	return free
 }
 
 var _ SeatAllocator = (*SeatGenerator)(nil)
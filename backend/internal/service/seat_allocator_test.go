@@ -4,22 +4,31 @@ import (
 	"bookcabin-voucher/config"
 	"bookcabin-voucher/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
 	"testing"
 )
 
+const (
+	atr          model.AircraftType = "ATR"
+	airbus320    model.AircraftType = "Airbus 320"
+	boeing737Max model.AircraftType = "Boeing 737 Max"
+)
+
 func setupTestLayout(t *testing.T) *SeatGenerator {
 	cfg := config.LoadConfig()
 
 	layoutPath := cfg.SeatLayoutPath
 	assert.NotEmpty(t, layoutPath)
 
-	gen := NewSeatAllocator(layoutPath)
+	gen, err := NewSeatAllocator(layoutPath, nil, nil)
+	require.NoError(t, err)
 	return gen
 }
 
 func TestGenerateSeats_Success(t *testing.T) {
 	gen := setupTestLayout(t)
-	seats, err := gen.GenerateSeats(model.Airbus320, 3, make([]string, 0))
+	seats, err := gen.GenerateSeats(t.Context(), airbus320, 3, make([]string, 0), "")
 
 	assert.NoError(t, err)
 	assert.Len(t, seats, 3)
@@ -27,18 +36,142 @@ func TestGenerateSeats_Success(t *testing.T) {
 
 func TestGenerateSeats_UnknownAircraft(t *testing.T) {
 	gen := setupTestLayout(t)
-	seats, err := gen.GenerateSeats("some-unknown", 3, make([]string, 0))
+	seats, err := gen.GenerateSeats(t.Context(), "some-unknown", 3, make([]string, 0), "")
 
 	assert.Error(t, err)
 	assert.Nil(t, seats)
-	assert.Contains(t, err.Error(), "unknown aircraft")
+	assert.ErrorIs(t, err, ErrUnknownAircraft)
 }
 
 func TestGenerateSeats_InsufficientSeats(t *testing.T) {
 	gen := setupTestLayout(t)
-	seats, err := gen.GenerateSeats(model.Airbus320, 50000000, make([]string, 0))
+	seats, err := gen.GenerateSeats(t.Context(), airbus320, 50000000, make([]string, 0), "")
+
+	assert.Error(t, err)
+	assert.Nil(t, seats)
+	assert.ErrorIs(t, err, ErrNotEnoughSeats)
+}
+
+func TestGenerateSeats_OnlyExistingSeats(t *testing.T) {
+	gen := newSeatGenerator(map[model.AircraftType]model.AircraftLayout{
+		"Tiny": {
+			StartRow:     12,
+			EndRow:       14,
+			Seats:        []string{"A", "B"},
+			SkippedRows:  []int{13},
+			BlockedSeats: []string{"12B"},
+			RowSeats:     map[int][]string{14: {"C"}},
+		},
+	}, nil)
+
+	seats, err := gen.GenerateSeats(t.Context(), "Tiny", 2, make([]string, 0), "")
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"12A", "14C"}, seats)
+
+	// the blocked seat is not capacity either
+	info, ok := gen.Resolve("Tiny")
+	require.True(t, ok)
+	assert.Equal(t, 2, info.Seats)
+}
+
+func TestSeatMap_SectionsAndExitRows(t *testing.T) {
+	layout := model.AircraftLayout{
+		StartRow: 1,
+		EndRow:   3,
+		Seats:    []string{"A", "B", "C"},
+		ExitRows: []int{3},
+		Sections: []model.CabinSection{
+			{Name: "business", StartRow: 1, EndRow: 1, Seats: []string{"A", "C"}},
+			{Name: "economy", StartRow: 2, EndRow: 3},
+		},
+	}
+
+	seats := layout.SeatMap()
+
+	assert.Len(t, seats, 8)
+	assert.Equal(t, model.Seat{Row: 1, Column: 1, Letter: "C", Section: "business", Window: true}, seats[1])
+	assert.Equal(t, model.Seat{Row: 3, Letter: "A", Section: "economy", ExitRow: true, Window: true}, seats[5])
+}
+
+func TestGenerateSeats_NearlyFullAircraft(t *testing.T) {
+	gen := setupTestLayout(t)
+	var taken []string
+	for _, seat := range gen.current.Load().seatMaps[atr] {
+		taken = append(taken, seat.Code())
+	}
+	free := []string{taken[0], taken[len(taken)-1]}
+
+	seats, err := gen.GenerateSeats(t.Context(), atr, 2, taken[1:len(taken)-1], "")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, free, seats)
 
+	seats, err = gen.GenerateSeats(t.Context(), atr, 3, taken[1:len(taken)-1], "")
 	assert.Error(t, err)
 	assert.Nil(t, seats)
-	assert.Contains(t, err.Error(), "not enough available seats")
+	assert.ErrorIs(t, err, ErrNotEnoughSeats)
+}
+
+func TestGenerateSeats_SeededIsReproducible(t *testing.T) {
+	layoutPath := config.LoadConfig().SeatLayoutPath
+	first, err := NewSeatAllocator(layoutPath, rand.NewSource(42), nil)
+	require.NoError(t, err)
+	second, err := NewSeatAllocator(layoutPath, rand.NewSource(42), nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		a, err := first.GenerateSeats(t.Context(), boeing737Max, 3, []string{"1A"}, "")
+		assert.NoError(t, err)
+		b, err := second.GenerateSeats(t.Context(), boeing737Max, 3, []string{"1A"}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, a, b)
+	}
+}
+
+func TestResolveAircraft_Aliases(t *testing.T) {
+	gen := setupTestLayout(t)
+
+	for _, name := range []string{"Airbus 320", "A320", "a320"} {
+		info, ok := gen.Resolve(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, airbus320, info.Type)
+	}
+	_, ok := gen.Resolve("Concorde")
+	assert.False(t, ok)
+
+	var types []model.AircraftType
+	for _, info := range gen.Aircraft() {
+		types = append(types, info.Type)
+	}
+	assert.Equal(t, []model.AircraftType{atr, airbus320, boeing737Max}, types)
+}
+
+func TestNewSeatAllocator_UnknownDefaultStrategy(t *testing.T) {
+	layoutPath := config.LoadConfig().SeatLayoutPath
+	gen, err := NewSeatAllocator(layoutPath, nil, map[string]string{"ATR": "middle-only"})
+
+	assert.Error(t, err)
+	assert.Nil(t, gen)
+	assert.Contains(t, err.Error(), "unknown default seat strategy")
+}
+
+func TestNewSeatAllocator_DefaultStrategyByAlias(t *testing.T) {
+	layoutPath := config.LoadConfig().SeatLayoutPath
+	gen, err := NewSeatAllocator(layoutPath, nil, map[string]string{"a320": StrategyFrontToBack})
+	require.NoError(t, err)
+
+	seats, err := gen.GenerateSeats(t.Context(), airbus320, 3, nil, "")
+	require.NoError(t, err)
+	want, err := gen.GenerateSeats(t.Context(), airbus320, 3, nil, StrategyFrontToBack)
+	require.NoError(t, err)
+	assert.Equal(t, want, seats)
+}
+
+func TestNewSeatAllocator_DefaultStrategyUnknownAircraft(t *testing.T) {
+	layoutPath := config.LoadConfig().SeatLayoutPath
+	gen, err := NewSeatAllocator(layoutPath, nil, map[string]string{"Concorde": StrategyWindow})
+
+	assert.Error(t, err)
+	assert.Nil(t, gen)
+	assert.Contains(t, err.Error(), `unknown aircraft "Concorde"`)
 }
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyWindow      = "window"
+	StrategyAisle       = "aisle"
+	StrategySameRow     = "same-row"
+	StrategyFrontToBack = "front-to-back"
+	StrategyBackToFront = "back-to-front"
+	StrategyAvoidExit   = "avoid-exit-rows"
+)
+
+// SeatStrategy picks count seats out of free, which is ordered front to back.
+// Implementations may assume count <= len(free).
+type SeatStrategy interface {
+	Pick(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error)
+}
+
+// SeatStrategyFunc adapts a plain function to SeatStrategy.
+type SeatStrategyFunc func(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error)
+
+func (f SeatStrategyFunc) Pick(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error) {
+	return f(free, count, rng)
+}
+
+var strategies = map[string]SeatStrategy{
+	StrategyRandom:      SeatStrategyFunc(pickRandom),
+	StrategyWindow:      preferring(func(s model.Seat) bool { return s.Window }),
+	StrategyAisle:       preferring(func(s model.Seat) bool { return s.Aisle }),
+	StrategyAvoidExit:   preferring(func(s model.Seat) bool { return !s.ExitRow }),
+	StrategySameRow:     SeatStrategyFunc(pickSameRow),
+	StrategyFrontToBack: SeatStrategyFunc(pickFrontToBack),
+	StrategyBackToFront: SeatStrategyFunc(pickBackToFront),
+}
+
+// RegisterStrategy makes a strategy selectable by name, replacing any existing one.
+// It is meant to be called during start-up, before seats are generated.
+func RegisterStrategy(name string, strategy SeatStrategy) {
+	strategies[name] = strategy
+}
+
+// LookupStrategy returns the strategy registered under name.
+func LookupStrategy(name string) (SeatStrategy, bool) {
+	strategy, ok := strategies[name]
+	return strategy, ok
+}
+
+// StrategyNames lists the registered strategies in alphabetical order.
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pickRandom draws count seats uniformly using a partial Fisher-Yates shuffle.
+func pickRandom(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error) {
+	pool := append([]model.Seat(nil), free...)
+	for i := 0; i < count; i++ {
+		j := i + rng.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:count], nil
+}
+
+// preferring draws randomly from the seats matching want first and only
+// tops up from the rest when there are not enough of them.
+func preferring(want func(model.Seat) bool) SeatStrategy {
+	return SeatStrategyFunc(func(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error) {
+		var preferred, others []model.Seat
+		for _, seat := range free {
+			if want(seat) {
+				preferred = append(preferred, seat)
+			} else {
+				others = append(others, seat)
+			}
+		}
+		if len(preferred) >= count {
+			return pickRandom(preferred, count, rng)
+		}
+		rest, err := pickRandom(others, count-len(preferred), rng)
+		if err != nil {
+			return nil, err
+		}
+		return append(preferred, rest...), nil
+	})
+}
+
+// pickSameRow seats everyone next to each other in one row. Blocks that do
+// not straddle an aisle are preferred over ones that do.
+func pickSameRow(free []model.Seat, count int, rng *rand.Rand) ([]model.Seat, error) {
+	var together, acrossAisle [][]model.Seat
+	for start := 0; start+count <= len(free); start++ {
+		block := free[start : start+count]
+		if !adjacent(block) {
+			continue
+		}
+		if crossesAisle(block) {
+			acrossAisle = append(acrossAisle, block)
+		} else {
+			together = append(together, block)
+		}
+	}
+
+	candidates := together
+	if len(candidates) == 0 {
+		candidates = acrossAisle
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w in a single row", ErrNotEnoughSeats)
+	}
+	return candidates[rng.Intn(len(candidates))], nil
+}
+
+// adjacent reports whether block is an unbroken run of seats in one row.
+func adjacent(block []model.Seat) bool {
+	for i := 1; i < len(block); i++ {
+		if block[i].Row != block[0].Row || block[i].Column != block[i-1].Column+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// crossesAisle reports whether an aisle separates any two neighbours in block.
+func crossesAisle(block []model.Seat) bool {
+	for i := 1; i < len(block); i++ {
+		if block[i-1].Aisle && block[i].Aisle {
+			return true
+		}
+	}
+	return false
+}
+
+func pickFrontToBack(free []model.Seat, count int, _ *rand.Rand) ([]model.Seat, error) {
+	return free[:count], nil
+}
+
+func pickBackToFront(free []model.Seat, count int, _ *rand.Rand) ([]model.Seat, error) {
+	picked := make([]model.Seat, 0, count)
+	for i := len(free) - 1; len(picked) < count; i-- {
+		picked = append(picked, free[i])
+	}
+	return picked, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func setupStrategyLayout() *SeatGenerator {
+	return newSeatGenerator(map[model.AircraftType]model.AircraftLayout{
+		"Small": {
+			StartRow:   1,
+			EndRow:     4,
+			Seats:      []string{"A", "B", "C", "D", "E", "F"},
+			AisleAfter: []string{"C"},
+			ExitRows:   []int{2, 3},
+		},
+	}, rand.NewSource(1))
+}
+
+func TestStrategy_Window(t *testing.T) {
+	gen := setupStrategyLayout()
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 8, make([]string, 0), StrategyWindow)
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1A", "1F", "2A", "2F", "3A", "3F", "4A", "4F"}, seats)
+}
+
+func TestStrategy_AisleFallsBackWhenExhausted(t *testing.T) {
+	gen := setupStrategyLayout()
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 3, []string{"1C", "1D", "2C", "2D", "3C", "3D", "4C"}, StrategyAisle)
+
+	assert.NoError(t, err)
+	assert.Len(t, seats, 3)
+	assert.Contains(t, seats, "4D")
+}
+
+func TestStrategy_AvoidExitRows(t *testing.T) {
+	gen := setupStrategyLayout()
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 12, make([]string, 0), StrategyAvoidExit)
+
+	assert.NoError(t, err)
+	for _, seat := range seats {
+		assert.Regexp(t, `^[14][A-F]$`, seat)
+	}
+}
+
+func TestStrategy_SameRowKeepsBlockTogether(t *testing.T) {
+	gen := setupStrategyLayout()
+	// row 1 only has D-F free side by side, the rest is broken up
+	existing := []string{"1A", "1C", "2B", "2E", "3B", "3E", "4B", "4E"}
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 3, existing, StrategySameRow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1D", "1E", "1F"}, seats)
+}
+
+func TestStrategy_SameRowNoBlock(t *testing.T) {
+	gen := setupStrategyLayout()
+	existing := []string{"1B", "1E", "2B", "2E", "3B", "3E", "4B", "4E"}
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 3, existing, StrategySameRow)
+
+	assert.Error(t, err)
+	assert.Nil(t, seats)
+	assert.Contains(t, err.Error(), "single row")
+	assert.ErrorIs(t, err, ErrNotEnoughSeats)
+}
+
+func TestStrategy_BackToFront(t *testing.T) {
+	gen := setupStrategyLayout()
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 3, []string{"4F"}, StrategyBackToFront)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4E", "4D", "4C"}, seats)
+}
+
+func TestStrategy_Unknown(t *testing.T) {
+	gen := setupStrategyLayout()
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 3, make([]string, 0), "middle-only")
+
+	assert.Error(t, err)
+	assert.Nil(t, seats)
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+}
+
+func TestStrategy_AircraftDefault(t *testing.T) {
+	gen := setupStrategyLayout()
+	gen.strategies = map[model.AircraftType]string{"Small": StrategyFrontToBack}
+	seats, err := gen.GenerateSeats(t.Context(), "Small", 2, []string{"1A"}, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1B", "1C"}, seats)
+}
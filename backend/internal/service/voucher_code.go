@@ -0,0 +1,84 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Voucher codes are twelve Crockford base32 symbols printed as XXXX-XXXX-XXXX:
+// eleven drawn from crypto/rand (55 bits) and a Luhn mod 32 check symbol that
+// catches any single mistyped symbol and most swapped neighbours.
+const (
+	voucherAlphabet    = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	voucherCodeSymbols = 12
+	voucherGroupSize   = 4
+)
+
+// NewVoucherCode returns a fresh random voucher code.
+func NewVoucherCode() (string, error) {
+	symbols := make([]byte, voucherCodeSymbols-1, voucherCodeSymbols)
+	max := big.NewInt(int64(len(voucherAlphabet)))
+	for i := range symbols {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate voucher code: %w", err)
+		}
+		symbols[i] = voucherAlphabet[n.Int64()]
+	}
+	symbols = append(symbols, voucherAlphabet[voucherCheckValue(string(symbols))])
+	return groupVoucherCode(string(symbols)), nil
+}
+
+// NormalizeVoucherCode turns a code as typed by a person into its printed form.
+// Case, dashes and spaces are ignored and the look-alikes I, L and O are read
+// as 1, 1 and 0. It reports false when the code is malformed or its check symbol
+// does not match.
+func NormalizeVoucherCode(code string) (string, bool) {
+	var symbols strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch r {
+		case '-', ' ':
+			continue
+		case 'I', 'L':
+			r = '1'
+		case 'O':
+			r = '0'
+		}
+		if !strings.ContainsRune(voucherAlphabet, r) {
+			return "", false
+		}
+		symbols.WriteRune(r)
+	}
+
+	plain := symbols.String()
+	if len(plain) != voucherCodeSymbols {
+		return "", false
+	}
+	body, check := plain[:len(plain)-1], plain[len(plain)-1]
+	if voucherAlphabet[voucherCheckValue(body)] != check {
+		return "", false
+	}
+	return groupVoucherCode(plain), true
+}
+
+// voucherCheckValue computes the Luhn mod 32 check value of body.
+func voucherCheckValue(body string) int {
+	n := len(voucherAlphabet)
+	factor, sum := 2, 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(voucherAlphabet, body[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return (n - sum%n) % n
+}
+
+func groupVoucherCode(plain string) string {
+	groups := make([]string, 0, len(plain)/voucherGroupSize)
+	for i := 0; i < len(plain); i += voucherGroupSize {
+		groups = append(groups, plain[i:min(i+voucherGroupSize, len(plain))])
+	}
+	return strings.Join(groups, "-")
+}
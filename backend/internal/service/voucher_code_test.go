@@ -0,0 +1,59 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewVoucherCode(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := NewVoucherCode()
+		require.NoError(t, err)
+		assert.Regexp(t, format, code)
+		assert.False(t, seen[code], "duplicate code %s", code)
+		seen[code] = true
+
+		normalized, ok := NormalizeVoucherCode(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, code, normalized)
+	}
+}
+
+func TestNormalizeVoucherCode(t *testing.T) {
+	code, err := NewVoucherCode()
+	require.NoError(t, err)
+
+	typed := strings.ToLower(strings.ReplaceAll(code, "-", " "))
+	typed = strings.NewReplacer("1", "l", "0", "o").Replace(typed)
+	normalized, ok := NormalizeVoucherCode(typed)
+	assert.True(t, ok)
+	assert.Equal(t, code, normalized)
+
+	for _, bad := range []string{"", "ABCD-EFGH", "ABCD-EFGH-JKMN-P", "ABCD-EFGH-JKM!", "ABCD-EFGH-JKMU"} {
+		_, ok := NormalizeVoucherCode(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestNormalizeVoucherCode_DetectsTypos(t *testing.T) {
+	code, err := NewVoucherCode()
+	require.NoError(t, err)
+	plain := strings.ReplaceAll(code, "-", "")
+
+	// every single-symbol substitution is caught by the check symbol
+	for i := range plain {
+		for _, r := range voucherAlphabet {
+			if byte(r) == plain[i] {
+				continue
+			}
+			typo := plain[:i] + string(r) + plain[i+1:]
+			_, ok := NormalizeVoucherCode(typo)
+			assert.False(t, ok, "%s accepted for %s", typo, plain)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"io"
+)
+
+// VoucherRenderer prints issued seats for the crew.
+type VoucherRenderer interface {
+	// Render writes the vouchers to w, one page per voucher.
+	Render(w io.Writer, vouchers []model.Voucher) error
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultVoucherFont     = "Helvetica"
+	defaultVoucherFontSize = 10.0
+	voucherRightMargin     = 10.0 // mm kept free when an element has no width
+	qrPrintScale           = 8    // pixels per QR module in the embedded image
+)
+
+var voucherFonts = map[string]bool{"helvetica": true, "arial": true, "times": true, "courier": true}
+
+// PDFVoucherRenderer prints vouchers as a PDF laid out by a VoucherTemplate.
+type PDFVoucherRenderer struct {
+	layout model.VoucherTemplate
+	texts  []*template.Template // parsed Text of each element, same order as layout.Elements
+}
+
+// NewVoucherRenderer loads the voucher template at path.
+func NewVoucherRenderer(path string) (*PDFVoucherRenderer, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read voucher template file: %w", err)
+	}
+	return ParseVoucherTemplate(file)
+}
+
+// ParseVoucherTemplate decodes a voucher template, rejecting unknown keys, and
+// checks that every element renders against a sample voucher.
+func ParseVoucherTemplate(data []byte) (*PDFVoucherRenderer, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var layout model.VoucherTemplate
+	if err := decoder.Decode(&layout); err != nil {
+		return nil, fmt.Errorf("invalid voucher template: %w", err)
+	}
+	if layout.PageWidth <= 0 || layout.PageHeight <= 0 {
+		return nil, fmt.Errorf("invalid voucher template: pageWidth and pageHeight must be positive")
+	}
+	if layout.Font == "" {
+		layout.Font = defaultVoucherFont
+	}
+	if !voucherFonts[strings.ToLower(layout.Font)] {
+		return nil, fmt.Errorf("invalid voucher template: font %q is not a PDF core font", layout.Font)
+	}
+	if len(layout.Elements) == 0 {
+		return nil, fmt.Errorf("invalid voucher template: no elements")
+	}
+
+	sample := model.Voucher{Code: "X", CrewName: "X", CrewID: "X", FlightNumber: "X", FlightDate: "X", Aircraft: "X", Seat: "X", IssuedAt: time.Now(), Token: "X", BCBP: "X"}
+	texts := make([]*template.Template, 0, len(layout.Elements))
+	for i, element := range layout.Elements {
+		if strings.Trim(strings.ToUpper(element.Style), "BIU") != "" {
+			return nil, fmt.Errorf("invalid voucher template: elements[%d].style must be made of B, I and U", i)
+		}
+		if element.QR && element.Width <= 0 {
+			return nil, fmt.Errorf("invalid voucher template: elements[%d] is a QR code and needs a width", i)
+		}
+		switch strings.ToUpper(element.Align) {
+		case "", "L", "C", "R":
+		default:
+			return nil, fmt.Errorf("invalid voucher template: elements[%d].align must be L, C or R", i)
+		}
+		text, err := template.New(fmt.Sprintf("elements[%d]", i)).Option("missingkey=error").Parse(element.Text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid voucher template: %w", err)
+		}
+		if err := text.Execute(io.Discard, sample); err != nil {
+			return nil, fmt.Errorf("invalid voucher template: %w", err)
+		}
+		texts = append(texts, text)
+	}
+	return &PDFVoucherRenderer{layout: layout, texts: texts}, nil
+}
+
+func (r *PDFVoucherRenderer) Render(w io.Writer, vouchers []model.Voucher) error {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: r.layout.PageWidth, Ht: r.layout.PageHeight},
+	})
+	pdf.SetMargins(0, 0, voucherRightMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Crew seat vouchers", true)
+	// core fonts only cover cp1252; translate so names like "Zoë" print correctly
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for _, voucher := range vouchers {
+		pdf.AddPage()
+		if r.layout.Border {
+			pdf.Rect(2, 2, r.layout.PageWidth-4, r.layout.PageHeight-4, "D")
+		}
+		for i, element := range r.layout.Elements {
+			var text strings.Builder
+			if err := r.texts[i].Execute(&text, voucher); err != nil {
+				return fmt.Errorf("failed to render voucher for seat %s: %w", voucher.Seat, err)
+			}
+			if element.QR {
+				if err := r.drawQR(pdf, fmt.Sprintf("qr-%d-%d", pdf.PageNo(), i), text.String(), element); err != nil {
+					return fmt.Errorf("failed to render voucher for seat %s: %w", voucher.Seat, err)
+				}
+				continue
+			}
+			size := element.Size
+			if size == 0 {
+				size = defaultVoucherFontSize
+			}
+			pdf.SetFont(r.layout.Font, strings.ToUpper(element.Style), size)
+			_, lineHeight := pdf.GetFontSize()
+
+			border := ""
+			if element.Box {
+				border = "1"
+			}
+			pdf.SetXY(element.X, element.Y)
+			pdf.CellFormat(element.Width, lineHeight*1.5, translate(text.String()), border, 0, strings.ToUpper(element.Align)+"M", false, 0, "")
+		}
+	}
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write voucher PDF: %w", err)
+	}
+	return nil
+}
+
+// drawQR places content as a QR code element.Width mm square, leaving the
+// element out when the voucher has nothing to encode, e.g. no token.
+func (r *PDFVoucherRenderer) drawQR(pdf *gofpdf.Fpdf, name, content string, element model.VoucherElement) error {
+	if content == "" {
+		return nil
+	}
+	code, err := EncodeQR(content)
+	if err != nil {
+		return err
+	}
+	var img bytes.Buffer
+	if err := png.Encode(&img, code.Image(qrPrintScale)); err != nil {
+		return err
+	}
+	pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, &img)
+	pdf.ImageOptions(name, element.X, element.Y, element.Width, element.Width, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	return pdf.Error()
+}
+
+var _ VoucherRenderer = (*PDFVoucherRenderer)(nil)
@@ -0,0 +1,12 @@
+package service
+
+import "bookcabin-voucher/internal/model"
+
+// VoucherSigner issues vouchers as signed tokens that can be checked without
+// calling the API.
+type VoucherSigner interface {
+	// Sign returns the voucher as a compact JWS signed with the current key.
+	Sign(voucher model.Voucher) (string, error)
+	// PublicKeys lists every key a live token may be signed with, the current key first.
+	PublicKeys() []model.VoucherKey
+}
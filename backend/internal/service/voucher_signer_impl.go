@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sort"
+	"time"
+)
+
+const voucherTokenIssuer = "bookcabin-voucher"
+
+var ErrUnknownVoucherKey = errors.New("voucher token signed with an unknown key")
+
+// VoucherClaims is the payload of a voucher token. The voucher code, when the
+// seat has one, is the token ID.
+type VoucherClaims struct {
+	FlightNumber string `json:"flt"`
+	FlightDate   string `json:"date"` // DD-MM-YY
+	Seat         string `json:"seat"`
+	CrewID       string `json:"crew"`
+	jwt.RegisteredClaims
+}
+
+// Ed25519VoucherSigner signs voucher tokens with Ed25519 (JWS alg EdDSA).
+type Ed25519VoucherSigner struct {
+	keyID string
+	key   ed25519.PrivateKey
+	keys  []model.VoucherKey // current key first
+	grace time.Duration
+}
+
+// NewVoucherSigner signs with signingKeys[keyID]; keyID may be left empty when
+// there is only one signing key. signingKeys holds base64 Ed25519 seeds and
+// verifyKeys base64 public keys of retired keys, both by key ID. All of them
+// are published so tokens issued before a rotation keep verifying. Without any
+// signing key signing is disabled and the signer is nil. Tokens expire grace
+// after the end of the flight date.
+func NewVoucherSigner(keyID string, signingKeys, verifyKeys map[string]string, grace time.Duration) (VoucherSigner, error) {
+	if len(signingKeys) == 0 {
+		if len(verifyKeys) > 0 {
+			return nil, errors.New("voucher verify keys are configured without a signing key")
+		}
+		return nil, nil
+	}
+
+	private := make(map[string]ed25519.PrivateKey, len(signingKeys))
+	for id, encoded := range signingKeys {
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid voucher signing key %q: want a base64 %d byte Ed25519 seed", id, ed25519.SeedSize)
+		}
+		private[id] = ed25519.NewKeyFromSeed(seed)
+	}
+
+	if keyID == "" && len(private) == 1 {
+		for id := range private {
+			keyID = id
+		}
+	}
+	key, ok := private[keyID]
+	if !ok {
+		return nil, fmt.Errorf("voucher signing key %q is not configured", keyID)
+	}
+
+	signer := &Ed25519VoucherSigner{
+		keyID: keyID,
+		key:   key,
+		keys:  []model.VoucherKey{{ID: keyID, PublicKey: key.Public().(ed25519.PublicKey)}},
+		grace: grace,
+	}
+	var retired []model.VoucherKey
+	for id, other := range private {
+		if id != keyID {
+			retired = append(retired, model.VoucherKey{ID: id, PublicKey: other.Public().(ed25519.PublicKey)})
+		}
+	}
+	for id, encoded := range verifyKeys {
+		if _, ok := private[id]; ok {
+			return nil, fmt.Errorf("voucher key %q is configured as both a signing and a verify key", id)
+		}
+		public, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(public) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid voucher verify key %q: want a base64 %d byte Ed25519 public key", id, ed25519.PublicKeySize)
+		}
+		retired = append(retired, model.VoucherKey{ID: id, PublicKey: public})
+	}
+	sort.Slice(retired, func(i, j int) bool { return retired[i].ID < retired[j].ID })
+	signer.keys = append(signer.keys, retired...)
+	return signer, nil
+}
+
+func (s *Ed25519VoucherSigner) Sign(voucher model.Voucher) (string, error) {
+	flightDate, err := time.Parse("02-01-06", voucher.FlightDate)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign voucher for seat %s: %w", voucher.Seat, err)
+	}
+	issuedAt := voucher.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, VoucherClaims{
+		FlightNumber: voucher.FlightNumber,
+		FlightDate:   voucher.FlightDate,
+		Seat:         voucher.Seat,
+		CrewID:       voucher.CrewID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    voucherTokenIssuer,
+			ID:        voucher.Code,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(flightDate.AddDate(0, 0, 1).Add(s.grace)),
+		},
+	})
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign voucher for seat %s: %w", voucher.Seat, err)
+	}
+	return signed, nil
+}
+
+func (s *Ed25519VoucherSigner) PublicKeys() []model.VoucherKey {
+	return s.keys
+}
+
+// ParseVoucherToken verifies a voucher token against keys and returns its
+// claims. Expired tokens, tokens of another issuer and tokens not signed with
+// EdDSA are rejected.
+func ParseVoucherToken(token string, keys []model.VoucherKey) (*VoucherClaims, error) {
+	var claims VoucherClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		keyID, _ := t.Header["kid"].(string)
+		for _, key := range keys {
+			if key.ID == keyID {
+				return key.PublicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("%w %q", ErrUnknownVoucherKey, keyID)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(voucherTokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// ParseVoucherKeySet reads the Ed25519 keys of a JSON Web Key Set such as the
+// one served at /.well-known/jwks.json. Keys of other types are skipped.
+func ParseVoucherKeySet(data []byte) ([]model.VoucherKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			Kid string `json:"kid"`
+			X   string `json:"x"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	var keys []model.VoucherKey
+	for _, key := range set.Keys {
+		if key.Kty != "OKP" || key.Crv != "Ed25519" {
+			continue
+		}
+		public, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(public) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid key set: key %q is not an Ed25519 public key", key.Kid)
+		}
+		keys = append(keys, model.VoucherKey{ID: key.Kid, PublicKey: public})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid key set: no Ed25519 keys")
+	}
+	return keys, nil
+}
+
+// NewVoucherSigningKey returns a fresh Ed25519 seed, base64 encoded as
+// VOUCHER_SIGNING_KEYS expects it, and its public key as VOUCHER_VERIFY_KEYS does.
+func NewVoucherSigningKey() (seed, public string, err error) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate voucher signing key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key.Seed()), base64.StdEncoding.EncodeToString(pub), nil
+}
+
+var _ VoucherSigner = (*Ed25519VoucherSigner)(nil)
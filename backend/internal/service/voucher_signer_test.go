@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bookcabin-voucher/internal/model"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSigningKey(t *testing.T) (string, string) {
+	seed, public, err := NewVoucherSigningKey()
+	require.NoError(t, err)
+	return seed, public
+}
+
+func testVoucher() model.Voucher {
+	return model.Voucher{
+		Code:         "R7X6-5JZ3-03J1",
+		CrewID:       "98123",
+		FlightNumber: "JT692",
+		FlightDate:   time.Now().AddDate(0, 0, 3).Format("02-01-06"),
+		Seat:         "3B",
+		IssuedAt:     time.Now(),
+	}
+}
+
+func TestVoucherSigner_SignAndParse(t *testing.T) {
+	seed, _ := newTestSigningKey(t)
+	signer, err := NewVoucherSigner("", map[string]string{"2025-07": seed}, nil, 24*time.Hour)
+	require.NoError(t, err)
+
+	voucher := testVoucher()
+	token, err := signer.Sign(voucher)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(token, "."), 3)
+
+	claims, err := ParseVoucherToken(token, signer.PublicKeys())
+	require.NoError(t, err)
+	assert.Equal(t, voucher.Code, claims.ID)
+	assert.Equal(t, voucher.FlightNumber, claims.FlightNumber)
+	assert.Equal(t, voucher.FlightDate, claims.FlightDate)
+	assert.Equal(t, voucher.Seat, claims.Seat)
+	assert.Equal(t, voucher.CrewID, claims.CrewID)
+
+	flightDate, _ := time.Parse("02-01-06", voucher.FlightDate)
+	assert.Equal(t, flightDate.Add(48*time.Hour), claims.ExpiresAt.Time.UTC())
+
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"alg":"EdDSA","kid":"2025-07","typ":"JWT"}`, string(header))
+}
+
+func TestVoucherSigner_Rotation(t *testing.T) {
+	oldSeed, oldPublic := newTestSigningKey(t)
+	newSeed, _ := newTestSigningKey(t)
+
+	before, err := NewVoucherSigner("2025-01", map[string]string{"2025-01": oldSeed}, nil, time.Hour)
+	require.NoError(t, err)
+	oldToken, err := before.Sign(testVoucher())
+	require.NoError(t, err)
+
+	// the new key signs while the old one is still accepted
+	after, err := NewVoucherSigner("2025-07", map[string]string{"2025-01": oldSeed, "2025-07": newSeed}, nil, time.Hour)
+	require.NoError(t, err)
+	newToken, err := after.Sign(testVoucher())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-07", "2025-01"}, keyIDs(after.PublicKeys()))
+	for _, token := range []string{oldToken, newToken} {
+		_, err := ParseVoucherToken(token, after.PublicKeys())
+		assert.NoError(t, err)
+	}
+
+	// the old private key is dropped, its public key keeps old tokens valid
+	retired, err := NewVoucherSigner("", map[string]string{"2025-07": newSeed}, map[string]string{"2025-01": oldPublic}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-07", "2025-01"}, keyIDs(retired.PublicKeys()))
+	_, err = ParseVoucherToken(oldToken, retired.PublicKeys())
+	assert.NoError(t, err)
+
+	// without it, old tokens no longer verify
+	_, err = ParseVoucherToken(oldToken, retired.PublicKeys()[:1])
+	assert.ErrorIs(t, err, ErrUnknownVoucherKey)
+}
+
+func TestVoucherSigner_Config(t *testing.T) {
+	seed, public := newTestSigningKey(t)
+	other, _ := newTestSigningKey(t)
+
+	tests := map[string]struct {
+		keyID   string
+		signing map[string]string
+		verify  map[string]string
+	}{
+		"bad seed":              {signing: map[string]string{"a": "not base64"}},
+		"short seed":            {signing: map[string]string{"a": base64.StdEncoding.EncodeToString(make([]byte, 16))}},
+		"unknown key id":        {keyID: "b", signing: map[string]string{"a": seed}},
+		"ambiguous key":         {signing: map[string]string{"a": seed, "b": other}},
+		"bad verify key":        {signing: map[string]string{"a": seed}, verify: map[string]string{"b": seed + "AA"}},
+		"signing and verify id": {signing: map[string]string{"a": seed}, verify: map[string]string{"a": public}},
+		"verify only":           {verify: map[string]string{"a": public}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewVoucherSigner(tt.keyID, tt.signing, tt.verify, time.Hour)
+			assert.Error(t, err)
+		})
+	}
+
+	// without a signing key vouchers are not signed at all
+	signer, err := NewVoucherSigner("", nil, nil, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, signer)
+}
+
+func TestParseVoucherToken_Rejects(t *testing.T) {
+	seed, _ := newTestSigningKey(t)
+	signer, err := NewVoucherSigner("", map[string]string{"k1": seed}, nil, 0)
+	require.NoError(t, err)
+	token, err := signer.Sign(testVoucher())
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	expired := testVoucher()
+	expired.FlightDate = time.Now().AddDate(0, 0, -2).Format("02-01-06")
+	expiredToken, err := signer.Sign(expired)
+	require.NoError(t, err)
+
+	payload, _ := json.Marshal(map[string]any{"flt": "JT692", "seat": "1A", "iss": voucherTokenIssuer, "exp": time.Now().Add(time.Hour).Unix()})
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"k1"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": voucherTokenIssuer, "exp": time.Now().Add(time.Hour).Unix()}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	for name, bad := range map[string]string{
+		"tampered":    parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2],
+		"expired":     expiredToken,
+		"alg none":    unsigned,
+		"hmac":        hmac,
+		"truncated":   parts[0] + "." + parts[1],
+		"not a token": "R7X6-5JZ3-03J1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseVoucherToken(bad, signer.PublicKeys())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseVoucherKeySet(t *testing.T) {
+	seed, _ := newTestSigningKey(t)
+	signer, err := NewVoucherSigner("", map[string]string{"k1": seed}, nil, time.Hour)
+	require.NoError(t, err)
+	x := base64.RawURLEncoding.EncodeToString(signer.PublicKeys()[0].PublicKey)
+
+	keys, err := ParseVoucherKeySet([]byte(`{"keys":[
+		{"kty":"RSA","kid":"rsa","n":"AQAB","e":"AQAB"},
+		{"kty":"OKP","crv":"Ed25519","kid":"k1","alg":"EdDSA","use":"sig","x":"` + x + `"}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, signer.PublicKeys(), keys)
+
+	for _, bad := range []string{`not json`, `{"keys":[]}`, `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"AAAA"}]}`} {
+		_, err := ParseVoucherKeySet([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func keyIDs(keys []model.VoucherKey) []string {
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.ID)
+	}
+	return ids
+}
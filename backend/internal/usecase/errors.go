@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"bookcabin-voucher/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Domain errors returned by the usecases. Callers match them with errors.Is;
+// the returned error may wrap them with more detail. Seat allocation failures
+// surface as service.ErrUnknownAircraft, ErrUnknownStrategy and ErrNotEnoughSeats.
+var (
+	// ErrAssignmentNotFound is returned when a flight has no voucher assignment.
+	ErrAssignmentNotFound = errors.New("assignment not found for this flight and date")
+	// ErrAssignmentExists is returned when generating for a flight that already has an assignment without asking for seats to change.
+	ErrAssignmentExists = errors.New("assignment for this flight and date already exists and no seats to change")
+	// ErrSeatNotAssigned is returned when revoking a seat the flight's assignment does not hold.
+	ErrSeatNotAssigned = errors.New("seat is not assigned on this flight")
+	// ErrAssignmentMismatch is returned when a re-roll names another crew member or aircraft than the stored assignment.
+	ErrAssignmentMismatch = errors.New("request does not match the existing assignment")
+	// ErrInvalidSeats is returned when seats to change are not held by the assignment.
+	ErrInvalidSeats = errors.New("invalid seats to change")
+	// ErrTooManyVouchers is returned when more vouchers are requested than the aircraft allows.
+	ErrTooManyVouchers = errors.New("too many vouchers requested")
+	// ErrInvalidCursor is returned when a listing cursor is malformed or belongs to another sort order.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrConcurrentChange is returned when another request changed the flight's assignment at the same time; retrying is safe.
+	ErrConcurrentChange = errors.New("the flight was changed by a concurrent request")
+	// ErrVoucherNotFound is returned for a voucher code no seat was issued with.
+	ErrVoucherNotFound = errors.New("voucher not found")
+	// ErrVoucherMismatch is returned when a voucher is presented for another flight, date or seat than it was issued for.
+	ErrVoucherMismatch = errors.New("voucher does not match the flight and seat")
+	// ErrVoucherRedeemed is returned when redeeming a voucher that was already used.
+	ErrVoucherRedeemed = errors.New("voucher has already been redeemed")
+	// ErrVoucherRevoked is returned when redeeming a voucher whose seat was revoked or re-rolled.
+	ErrVoucherRevoked = errors.New("voucher has been revoked")
+	// ErrTokenSigningDisabled is returned when a signed voucher token is asked for but no signing key is configured.
+	ErrTokenSigningDisabled = errors.New("token signing is not configured")
+	// ErrAircraftRequired is returned when the aircraft cannot be inferred from an existing assignment.
+	ErrAircraftRequired = errors.New("aircraft is required for a flight without an assignment")
+)
+
+// FieldError describes one rejected value of a request.
+type FieldError struct {
+	Field   string // request field, e.g. "seats[1]"
+	Rule    string // short reason, e.g. "not_assigned"
+	Value   string
+	Message string
+}
+
+// ValidationError wraps a domain error with the request values that caused it.
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Message)
+	}
+	return e.Err.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// concurrentChange reports a write that lost a race with another request as ErrConcurrentChange.
+func concurrentChange(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return fmt.Errorf("%w: %w", ErrConcurrentChange, err)
+	}
+	return err
+}
@@ -3,9 +3,22 @@ package usecase
 import (
 	"bookcabin-voucher/internal/dto"
 	"bookcabin-voucher/internal/model"
+	"context"
 )
 
 type FlightUsecase interface {
-	CheckFlightExists(request dto.CheckFlightRequest) bool
-	GenerateAndAssignSeats(request dto.GenerateRequest) (*model.FlightAssignment, error)
+	CheckFlightExists(ctx context.Context, request dto.CheckFlightRequest) bool
+	GenerateAndAssignSeats(ctx context.Context, request dto.GenerateRequest) (*model.FlightAssignment, error)
+	GetAssignment(ctx context.Context, request dto.FlightPathRequest) (*model.FlightAssignment, error)
+	// ListAssignments returns one page of assignments and the cursor of the next page, "" on the last page.
+	ListAssignments(ctx context.Context, request dto.ListAssignmentsRequest) ([]model.FlightAssignment, string, error)
+	// RevokeAssignment voids a flight's assignment and frees its seats so the flight can be generated again.
+	RevokeAssignment(ctx context.Context, flight dto.FlightPathRequest, request dto.RevokeRequest) error
+	// RevokeSeat voids a single seat of an assignment and returns what remains of
+	// it. Revoking the last seat revokes the whole assignment and returns nil.
+	RevokeSeat(ctx context.Context, seat dto.SeatPathRequest, request dto.RevokeRequest) (*model.FlightAssignment, error)
+	// GetHistory returns the audit log of every assignment the flight has had, oldest first.
+	GetHistory(ctx context.Context, request dto.FlightPathRequest) ([]model.FlightAssignmentEvent, error)
+	ListAircraft(ctx context.Context) []model.AircraftInfo
+	GetSeatInventory(ctx context.Context, request dto.SeatInventoryRequest) (*model.SeatInventory, error)
 }
@@ -6,137 +6,598 @@ import (
 	"bookcabin-voucher/internal/repository"
 	"bookcabin-voucher/internal/service"
 	"bookcabin-voucher/internal/utils"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 )
 
+const (
+	defaultListLimit = 20
+	defaultListSort  = "-createdAt"
+)
+
+// defaultVoucherCount applies when neither the request nor the layout sets a count.
+const defaultVoucherCount = 3
+
 type flightUsecaseImpl struct {
 	repo    repository.FlightRepository
 	seatGen service.SeatAllocator
+	signer  service.VoucherSigner // nil issues no voucher tokens
 }
 
-func NewFlightUsecase(repo repository.FlightRepository, seatGen service.SeatAllocator) FlightUsecase {
+func NewFlightUsecase(repo repository.FlightRepository, seatGen service.SeatAllocator, signer service.VoucherSigner) FlightUsecase {
 	return &flightUsecaseImpl{
 		repo:    repo,
 		seatGen: seatGen,
+		signer:  signer,
 	}
 }
 
-func (u *flightUsecaseImpl) CheckFlightExists(request dto.CheckFlightRequest) bool {
-	return u.repo.CountByFlightAndDate(request.FlightNumber, request.Date) > 0
+func (u *flightUsecaseImpl) CheckFlightExists(ctx context.Context, request dto.CheckFlightRequest) bool {
+	return u.repo.CountByFlightAndDate(ctx, request.FlightNumber, request.Date) > 0
 }
 
-func (u *flightUsecaseImpl) GenerateAndAssignSeats(request dto.GenerateRequest) (*model.FlightAssignment, error) {
-	tx := u.repo.BeginTx()
-	count := u.repo.CountByFlightAndDateTx(tx, request.FlightNumber, request.Date)
+func (u *flightUsecaseImpl) GetAssignment(ctx context.Context, request dto.FlightPathRequest) (*model.FlightAssignment, error) {
+	assignments, err := u.repo.GetByFilter(ctx, dto.FlightFilter{
+		FlightNumber: request.FlightNumber,
+		Date:         request.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return nil, ErrAssignmentNotFound
+	}
+	return &assignments[0], nil
+}
 
-	//if not exist, create new
-	if count == 0 {
-		seats, err := u.seatGen.GenerateSeats(request.Aircraft, 3, make([]string, 0))
+func (u *flightUsecaseImpl) ListAssignments(ctx context.Context, request dto.ListAssignmentsRequest) ([]model.FlightAssignment, string, error) {
+	sort := request.Sort
+	if sort == "" {
+		sort = defaultListSort
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	filter := dto.AssignmentFilter{
+		FromDay:    utils.ISODate(request.From),
+		ToDay:      utils.ISODate(request.To),
+		CrewID:     request.CrewID,
+		Sort:       dto.AssignmentSort(strings.TrimPrefix(sort, "-")),
+		Descending: strings.HasPrefix(sort, "-"),
+		Limit:      limit + 1, // one extra row tells whether another page exists
+	}
+	for _, prefix := range []string{request.Airline, request.FlightNumber} {
+		if prefix != "" {
+			filter.FlightNumberPrefix = append(filter.FlightNumberPrefix, prefix)
+		}
+	}
+	if request.Aircraft != "" {
+		aircraft, ok := u.seatGen.Resolve(request.Aircraft)
+		if !ok {
+			return nil, "", service.ErrUnknownAircraft
+		}
+		filter.Aircraft = aircraft.Type
+	}
+	if request.Cursor != "" {
+		after, err := decodeCursor(request.Cursor, sort)
 		if err != nil {
-			log.Printf("[Usecase] Seat generation failed for %s: %v", request.Aircraft, err)
-			return nil, fmt.Errorf("failed to generate seats: %w", err)
+			return nil, "", err
 		}
+		filter.After = after
+	}
 
-		assignment := &model.FlightAssignment{
-			CrewName:     request.CrewName,
-			CrewID:       request.CrewID,
-			FlightNumber: request.FlightNumber,
-			FlightDate:   request.Date,
-			AircraftType: request.Aircraft,
-		}
+	assignments, err := u.repo.ListAssignments(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(assignments) <= limit {
+		return assignments, "", nil
+	}
+
+	assignments = assignments[:limit]
+	last := assignments[limit-1]
+	next := dto.AssignmentCursor{ID: last.ID}
+	switch filter.Sort {
+	case dto.SortByFlightDate:
+		next.Value = last.FlightDay
+	case dto.SortByFlightNumber:
+		next.Value = last.FlightNumber
+	}
+	return assignments, encodeCursor(next, sort), nil
+}
+
+// pageCursor is the opaque cursor handed to clients. It carries the sort it
+// was issued for so that it cannot be replayed against a different order.
+type pageCursor struct {
+	Sort string `json:"s"`
+	dto.AssignmentCursor
+}
+
+func encodeCursor(cursor dto.AssignmentCursor, sort string) string {
+	raw, _ := json.Marshal(pageCursor{Sort: sort, AssignmentCursor: cursor})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
 
-		//create assignment
-		assignment, err = u.repo.CreateTx(tx, assignment)
+func decodeCursor(encoded, sort string) (*dto.AssignmentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor.AssignmentCursor, nil
+}
+
+func (u *flightUsecaseImpl) RevokeAssignment(ctx context.Context, flight dto.FlightPathRequest, request dto.RevokeRequest) error {
+	err := u.repo.WithinTx(ctx, func(repo repository.FlightRepository) error {
+		if err := repo.LockFlight(ctx, flight.FlightNumber, flight.Date); err != nil {
+			return err
+		}
+		assignment, err := findAssignment(ctx, repo, flight)
 		if err != nil {
-			tx.Rollback()
-			log.Printf("[Usecase] Failed to persist assignment for %s: %v", request.FlightNumber, err)
-			return nil, fmt.Errorf("failed to create assignment in DB: %w", err)
+			return err
 		}
 
-		var seatAssignments []model.FlightSeatAssignment
-		for _, seat := range seats {
-			seatAssignments = append(seatAssignments, model.FlightSeatAssignment{
-				FlightAssignmentID: assignment.ID,
-				Seat:               seat,
-			})
+		seats := utils.ExtractSeats(assignment.SeatAssignments)
+		if err := repo.RevokeAssignment(ctx, assignment.ID, request.Actor, request.Reason); err != nil {
+			log.Printf("[Usecase] Failed to revoke assignment for %s: %v", flight.FlightNumber, err)
+			return err
+		}
+		if err := repo.SetSeatStatus(ctx, flight.FlightNumber, flight.Date, seats, model.SeatFree); err != nil {
+			log.Printf("[Usecase] Failed to update seat inventory for %s: %v", flight.FlightNumber, err)
+			return fmt.Errorf("failed to update seat inventory: %w", err)
 		}
 
-		//create seat assignment
-		err = u.repo.BulkCreateSeatAssignmentsTx(tx, seatAssignments)
+		event := newEvent(assignment.ID, flight.FlightNumber, flight.Date, model.EventRevoked, revokeMeta(request))
+		event.SeatsFrom = seats
+		event.Reason = request.Reason
+		if err := repo.AppendEvent(ctx, event); err != nil {
+			log.Printf("[Usecase] Failed to record event for %s: %v", flight.FlightNumber, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return concurrentChange(err)
+	}
+	log.Printf("[Usecase] Assignment for %s on %s revoked by %s: %s", flight.FlightNumber, flight.Date, request.Actor, request.Reason)
+	return nil
+}
+
+func (u *flightUsecaseImpl) RevokeSeat(ctx context.Context, seat dto.SeatPathRequest, request dto.RevokeRequest) (*model.FlightAssignment, error) {
+	retired := false
+	err := u.repo.WithinTx(ctx, func(repo repository.FlightRepository) error {
+		if err := repo.LockFlight(ctx, seat.FlightNumber, seat.Date); err != nil {
+			return err
+		}
+		assignment, err := findAssignment(ctx, repo, seat.FlightPathRequest)
 		if err != nil {
-			tx.Rollback()
-			log.Printf("[Usecase] Failed to create seat assignments for %s: %v", request.FlightNumber, err)
-			return nil, fmt.Errorf("failed to create seat assignments: %w", err)
+			return err
 		}
-	} else { //if exist will use update instead
-		seatsToChangeCount := len(request.SeatsToChange)
-		if seatsToChangeCount == 0 {
-			tx.Rollback()
-			log.Printf("[Usecase] Flight assignment already exists: %s on %s", request.FlightNumber, request.Date)
-			return nil, fmt.Errorf("assignment for this flight and date already exists and no seats to change")
+
+		held := false
+		for _, s := range assignment.SeatAssignments {
+			held = held || s.Seat == seat.Seat
+		}
+		if !held {
+			return ErrSeatNotAssigned
 		}
 
-		filter := dto.FlightFilter{
-			FlightNumber: request.FlightNumber,
-			Date:         request.Date,
-			Seats:        request.SeatsToChange,
+		if err := repo.RevokeSeats(ctx, assignment.ID, []string{seat.Seat}, request.Actor, request.Reason); err != nil {
+			log.Printf("[Usecase] Failed to revoke seat %s on %s: %v", seat.Seat, seat.FlightNumber, err)
+			return err
+		}
+		if err := repo.SetSeatStatus(ctx, seat.FlightNumber, seat.Date, []string{seat.Seat}, model.SeatFree); err != nil {
+			log.Printf("[Usecase] Failed to update seat inventory for %s: %v", seat.FlightNumber, err)
+			return fmt.Errorf("failed to update seat inventory: %w", err)
 		}
 
-		// Find the related assignment for this flight
-		assignments, err := u.repo.GetByFilterTx(tx, filter)
-		if err != nil || len(assignments) == 0 {
-			tx.Rollback()
-			log.Printf("[Usecase] No assignment found after deletion or error: %v", err)
-			return nil, fmt.Errorf("no matching assignment found after seat deletion")
+		event := newEvent(assignment.ID, seat.FlightNumber, seat.Date, model.EventSeatRevoked, revokeMeta(request))
+		event.SeatsFrom = []string{seat.Seat}
+		event.Reason = request.Reason
+		if err := repo.AppendEvent(ctx, event); err != nil {
+			log.Printf("[Usecase] Failed to record event for %s: %v", seat.FlightNumber, err)
+			return err
 		}
 
-		//generate new seats assignment
-		seatsToChange := utils.ExtractSeats(assignments[0].SeatAssignments)
-		seats, err := u.seatGen.GenerateSeats(request.Aircraft, seatsToChangeCount, seatsToChange)
+		// an assignment without seats would block generating the flight again
+		if len(assignment.SeatAssignments) > 1 {
+			return nil
+		}
+		if err := repo.RevokeAssignment(ctx, assignment.ID, request.Actor, request.Reason); err != nil {
+			log.Printf("[Usecase] Failed to revoke assignment for %s: %v", seat.FlightNumber, err)
+			return err
+		}
+		event = newEvent(assignment.ID, seat.FlightNumber, seat.Date, model.EventRevoked, revokeMeta(request))
+		event.Reason = request.Reason
+		if err := repo.AppendEvent(ctx, event); err != nil {
+			log.Printf("[Usecase] Failed to record event for %s: %v", seat.FlightNumber, err)
+			return err
+		}
+		retired = true
+		return nil
+	})
+	if err != nil {
+		return nil, concurrentChange(err)
+	}
+	log.Printf("[Usecase] Seat %s on %s %s revoked by %s: %s", seat.Seat, seat.FlightNumber, seat.Date, request.Actor, request.Reason)
+	if retired {
+		log.Printf("[Usecase] Assignment for %s on %s revoked with its last seat", seat.FlightNumber, seat.Date)
+		return nil, nil
+	}
+
+	return u.GetAssignment(ctx, seat.FlightPathRequest)
+}
+
+func (u *flightUsecaseImpl) GetHistory(ctx context.Context, request dto.FlightPathRequest) ([]model.FlightAssignmentEvent, error) {
+	events, err := u.repo.ListEvents(ctx, request.FlightNumber, request.Date)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrAssignmentNotFound
+	}
+	return events, nil
+}
+
+// newEvent starts the audit record of a change to an assignment.
+func newEvent(assignmentID uint, flightNumber, date string, kind model.AssignmentEventType, meta dto.RequestMeta) *model.FlightAssignmentEvent {
+	return &model.FlightAssignmentEvent{
+		FlightAssignmentID: assignmentID,
+		FlightNumber:       flightNumber,
+		FlightDate:         date,
+		Type:               kind,
+		Actor:              meta.Actor,
+		RequestID:          meta.RequestID,
+		ClientIP:           meta.ClientIP,
+		UserAgent:          meta.UserAgent,
+	}
+}
+
+// generateMeta attributes a generation to the crew member when no actor was sent.
+func generateMeta(request dto.GenerateRequest) dto.RequestMeta {
+	meta := request.Meta
+	if meta.Actor == "" {
+		meta.Actor = request.CrewID
+	}
+	return meta
+}
+
+// revokeMeta attributes a revocation to the actor named in its body.
+func revokeMeta(request dto.RevokeRequest) dto.RequestMeta {
+	meta := request.Meta
+	meta.Actor = request.Actor
+	return meta
+}
+
+// newSeatAssignments builds the rows of seats issued to an assignment, each
+// with a fresh voucher code. A re-rolled seat gets a new row and code; the
+// replaced row keeps the old code and is revoked with it.
+func newSeatAssignments(assignmentID uint, seats []string) ([]model.FlightSeatAssignment, error) {
+	seatAssignments := make([]model.FlightSeatAssignment, 0, len(seats))
+	for _, seat := range seats {
+		code, err := service.NewVoucherCode()
 		if err != nil {
-			log.Printf("[Usecase] Seat generation failed for %s: %v", request.Aircraft, err)
-			return nil, fmt.Errorf("failed to generate seats: %w", err)
+			return nil, err
+		}
+		seatAssignments = append(seatAssignments, model.FlightSeatAssignment{
+			FlightAssignmentID: assignmentID,
+			Seat:               seat,
+			Code:               code,
+		})
+	}
+	return seatAssignments, nil
+}
+
+// findAssignment loads the live assignment of a flight through repo.
+func findAssignment(ctx context.Context, repo repository.FlightRepository, flight dto.FlightPathRequest) (*model.FlightAssignment, error) {
+	assignments, err := repo.GetByFilter(ctx, dto.FlightFilter{FlightNumber: flight.FlightNumber, Date: flight.Date})
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return nil, ErrAssignmentNotFound
+	}
+	return &assignments[0], nil
+}
+
+func (u *flightUsecaseImpl) ListAircraft(ctx context.Context) []model.AircraftInfo {
+	return u.seatGen.Aircraft()
+}
+
+func (u *flightUsecaseImpl) GenerateAndAssignSeats(ctx context.Context, request dto.GenerateRequest) (*model.FlightAssignment, error) {
+	// store the layout key rather than whichever alias the client sent
+	aircraft, ok := u.seatGen.Resolve(string(request.Aircraft))
+	if !ok {
+		return nil, service.ErrUnknownAircraft
+	}
+	request.Aircraft = aircraft.Type
+
+	voucherCount, err := resolveVoucherCount(aircraft, request.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignment *model.FlightAssignment
+	err = u.repo.WithinTx(ctx, func(repo repository.FlightRepository) error {
+		// concurrent requests for the flight wait here, so they see each other's writes
+		if err := repo.LockFlight(ctx, request.FlightNumber, request.Date); err != nil {
+			return err
 		}
+		count := repo.CountByFlightAndDate(ctx, request.FlightNumber, request.Date)
 
-		// Delete existing seats
-		_, err = u.repo.DeleteSeatsByFilterTx(tx, filter)
+		// seats already taken on this flight by any assignment
+		occupied, err := repo.FindOccupiedSeats(ctx, request.FlightNumber, request.Date)
 		if err != nil {
-			tx.Rollback()
-			log.Printf("[Usecase] Failed to delete existing seats: %v", err)
-			return nil, fmt.Errorf("failed to delete seats: %w", err)
+			log.Printf("[Usecase] Failed to load seat inventory for %s: %v", request.FlightNumber, err)
+			return fmt.Errorf("failed to load seat inventory: %w", err)
 		}
 
-		var seatAssignments []model.FlightSeatAssignment
-		for _, seat := range seats {
-			seatAssignments = append(seatAssignments, model.FlightSeatAssignment{
-				FlightAssignmentID: assignments[0].ID,
-				Seat:               seat,
-			})
+		//if not exist, create new
+		if count == 0 {
+			err = u.createAssignment(ctx, repo, request, voucherCount, occupied)
+		} else {
+			//if exist will use update instead
+			err = u.reRollSeats(ctx, repo, request, occupied)
+		}
+		if err != nil {
+			return err
 		}
 
-		//insert new seats
-		err = u.repo.BulkCreateSeatAssignmentsTx(tx, seatAssignments)
+		// read back what was written while the flight is still locked
+		assignment, err = findAssignment(ctx, repo, dto.FlightPathRequest{FlightNumber: request.FlightNumber, Date: request.Date})
+		return err
+	})
+	if err != nil {
+		return nil, concurrentChange(err)
+	}
+
+	if err := u.signVouchers(assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// signVouchers attaches a signed voucher token to every seat of the assignment.
+func (u *flightUsecaseImpl) signVouchers(assignment *model.FlightAssignment) error {
+	if u.signer == nil {
+		return nil
+	}
+	for i := range assignment.SeatAssignments {
+		seat := &assignment.SeatAssignments[i]
+		token, err := u.signer.Sign(newVoucher(assignment, *seat))
 		if err != nil {
-			tx.Rollback()
-			log.Printf("[Usecase] Failed to re-create seats: %v", err)
-			return nil, fmt.Errorf("failed to re-create seat assignments: %w", err)
+			log.Printf("[Usecase] Failed to sign voucher for %s seat %s: %v", assignment.FlightNumber, seat.Seat, err)
+			return err
 		}
+		seat.Token = token
+	}
+	return nil
+}
+
+// createAssignment issues voucherCount fresh seats for a flight with no assignment yet.
+func (u *flightUsecaseImpl) createAssignment(ctx context.Context, repo repository.FlightRepository, request dto.GenerateRequest, voucherCount int, occupied []string) error {
+	seats, err := u.seatGen.GenerateSeats(ctx, request.Aircraft, voucherCount, occupied, request.Strategy)
+	if err != nil {
+		log.Printf("[Usecase] Seat generation failed for %s: %v", request.Aircraft, err)
+		return fmt.Errorf("failed to generate seats: %w", err)
+	}
+
+	assignment := &model.FlightAssignment{
+		CrewName:     request.CrewName,
+		CrewID:       request.CrewID,
+		FlightNumber: request.FlightNumber,
+		FlightDate:   request.Date,
+		FlightDay:    utils.ISODate(request.Date),
+		AircraftType: request.Aircraft,
+	}
+
+	//create assignment
+	assignment, err = repo.Create(ctx, assignment)
+	if err != nil {
+		log.Printf("[Usecase] Failed to persist assignment for %s: %v", request.FlightNumber, err)
+		return fmt.Errorf("failed to create assignment in DB: %w", err)
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	seatAssignments, err := newSeatAssignments(assignment.ID, seats)
+	if err != nil {
+		return err
 	}
 
-	currentFilter := dto.FlightFilter{
+	//create seat assignment
+	if err := repo.BulkCreateSeatAssignments(ctx, seatAssignments); err != nil {
+		log.Printf("[Usecase] Failed to create seat assignments for %s: %v", request.FlightNumber, err)
+		return fmt.Errorf("failed to create seat assignments: %w", err)
+	}
+
+	if err := repo.SetSeatStatus(ctx, request.FlightNumber, request.Date, seats, model.SeatIssued); err != nil {
+		log.Printf("[Usecase] Failed to update seat inventory for %s: %v", request.FlightNumber, err)
+		return fmt.Errorf("failed to update seat inventory: %w", err)
+	}
+
+	event := newEvent(assignment.ID, request.FlightNumber, request.Date, model.EventCreated, generateMeta(request))
+	event.SeatsTo = seats
+	if err := repo.AppendEvent(ctx, event); err != nil {
+		log.Printf("[Usecase] Failed to record event for %s: %v", request.FlightNumber, err)
+		return err
+	}
+	return nil
+}
+
+// reRollSeats replaces the requested seats of an existing assignment with new ones.
+func (u *flightUsecaseImpl) reRollSeats(ctx context.Context, repo repository.FlightRepository, request dto.GenerateRequest, occupied []string) error {
+	seatsToChangeCount := len(request.SeatsToChange)
+	if seatsToChangeCount == 0 {
+		log.Printf("[Usecase] Flight assignment already exists: %s on %s", request.FlightNumber, request.Date)
+		return ErrAssignmentExists
+	}
+
+	// Find the related assignment for this flight
+	assignment, err := findAssignment(ctx, repo, dto.FlightPathRequest{FlightNumber: request.FlightNumber, Date: request.Date})
+	if err != nil {
+		log.Printf("[Usecase] Failed to load assignment for %s: %v", request.FlightNumber, err)
+		return err
+	}
+
+	if err := checkReRoll(request, assignment); err != nil {
+		log.Printf("[Usecase] Rejected re-roll for %s on %s: %v", request.FlightNumber, request.Date, err)
+		return err
+	}
+	filter := dto.FlightFilter{
 		FlightNumber: request.FlightNumber,
 		Date:         request.Date,
+		Seats:        request.SeatsToChange,
 	}
 
-	// find the updated data, a guarantee will be there
-	assignments, _ := u.repo.GetByFilter(currentFilter)
+	//generate new seats assignment, avoiding the seats being replaced as well
+	existingSeats := utils.MergeSeats(utils.ExtractSeats(assignment.SeatAssignments), occupied)
+	seats, err := u.seatGen.GenerateSeats(ctx, request.Aircraft, seatsToChangeCount, existingSeats, request.Strategy)
+	if err != nil {
+		log.Printf("[Usecase] Seat generation failed for %s: %v", request.Aircraft, err)
+		return fmt.Errorf("failed to generate seats: %w", err)
+	}
 
-	return &assignments[0], nil
+	// Delete existing seats
+	if _, err := repo.DeleteSeatsByFilter(ctx, filter, generateMeta(request).Actor); err != nil {
+		log.Printf("[Usecase] Failed to delete existing seats: %v", err)
+		return fmt.Errorf("failed to delete seats: %w", err)
+	}
+
+	seatAssignments, err := newSeatAssignments(assignment.ID, seats)
+	if err != nil {
+		return err
+	}
+
+	//insert new seats
+	if err := repo.BulkCreateSeatAssignments(ctx, seatAssignments); err != nil {
+		log.Printf("[Usecase] Failed to re-create seats: %v", err)
+		return fmt.Errorf("failed to re-create seat assignments: %w", err)
+	}
+
+	// release the replaced seats before issuing the new ones
+	err = repo.SetSeatStatus(ctx, request.FlightNumber, request.Date, request.SeatsToChange, model.SeatFree)
+	if err == nil {
+		err = repo.SetSeatStatus(ctx, request.FlightNumber, request.Date, seats, model.SeatIssued)
+	}
+	if err != nil {
+		log.Printf("[Usecase] Failed to update seat inventory for %s: %v", request.FlightNumber, err)
+		return fmt.Errorf("failed to update seat inventory: %w", err)
+	}
+
+	event := newEvent(assignment.ID, request.FlightNumber, request.Date, model.EventReRolled, generateMeta(request))
+	event.SeatsFrom = request.SeatsToChange
+	event.SeatsTo = seats
+	if err := repo.AppendEvent(ctx, event); err != nil {
+		log.Printf("[Usecase] Failed to record event for %s: %v", request.FlightNumber, err)
+		return err
+	}
+	return nil
+}
+
+func (u *flightUsecaseImpl) GetSeatInventory(ctx context.Context, request dto.SeatInventoryRequest) (*model.SeatInventory, error) {
+	aircraft, ok := u.seatGen.Resolve(request.Aircraft)
+	if !ok {
+		// fall back to the aircraft the flight was assigned with
+		assignments, err := u.repo.GetByFilter(ctx, dto.FlightFilter{FlightNumber: request.FlightNumber, Date: request.Date})
+		if err != nil {
+			return nil, err
+		}
+		if len(assignments) == 0 {
+			return nil, ErrAircraftRequired
+		}
+		if aircraft, ok = u.seatGen.Resolve(string(assignments[0].AircraftType)); !ok {
+			aircraft = model.AircraftInfo{Type: assignments[0].AircraftType}
+		}
+	}
+	capacity := aircraft.Seats
+
+	seats, err := u.repo.GetSeatInventory(ctx, request.FlightNumber, request.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[model.SeatStatus]int{model.SeatFree: capacity}
+	for _, seat := range seats {
+		if seat.Status != model.SeatFree {
+			counts[seat.Status]++
+			counts[model.SeatFree]--
+		}
+	}
+
+	return &model.SeatInventory{
+		FlightNumber: request.FlightNumber,
+		FlightDate:   request.Date,
+		Aircraft:     aircraft.Type,
+		Capacity:     capacity,
+		Counts:       counts,
+		Seats:        seats,
+	}, nil
+}
+
+// checkReRoll makes sure a re-roll comes from the crew member and aircraft of
+// the stored assignment and only names seats that assignment holds, once each.
+func checkReRoll(request dto.GenerateRequest, assignment *model.FlightAssignment) error {
+	var mismatches []FieldError
+	if request.CrewID != assignment.CrewID {
+		mismatches = append(mismatches, FieldError{Field: "id", Rule: "mismatch", Value: request.CrewID,
+			Message: "crew ID does not match the existing assignment"})
+	}
+	if !strings.EqualFold(strings.TrimSpace(request.CrewName), strings.TrimSpace(assignment.CrewName)) {
+		mismatches = append(mismatches, FieldError{Field: "name", Rule: "mismatch", Value: request.CrewName,
+			Message: "crew name does not match the existing assignment"})
+	}
+	if request.Aircraft != assignment.AircraftType {
+		mismatches = append(mismatches, FieldError{Field: "aircraft", Rule: "mismatch", Value: string(request.Aircraft),
+			Message: fmt.Sprintf("aircraft %s does not match the assignment's %s", request.Aircraft, assignment.AircraftType)})
+	}
+	if len(mismatches) > 0 {
+		return &ValidationError{Err: ErrAssignmentMismatch, Fields: mismatches}
+	}
+
+	held := make(map[string]bool, len(assignment.SeatAssignments))
+	for _, s := range assignment.SeatAssignments {
+		held[s.Seat] = true
+	}
+	var invalid []FieldError
+	seen := make(map[string]bool, len(request.SeatsToChange))
+	for i, seat := range request.SeatsToChange {
+		field := fmt.Sprintf("seats[%d]", i)
+		switch {
+		case seen[seat]:
+			invalid = append(invalid, FieldError{Field: field, Rule: "duplicate", Value: seat,
+				Message: fmt.Sprintf("seat %s is listed more than once", seat)})
+		case !held[seat]:
+			invalid = append(invalid, FieldError{Field: field, Rule: "not_assigned", Value: seat,
+				Message: fmt.Sprintf("seat %s is not part of this assignment", seat)})
+		}
+		seen[seat] = true
+	}
+	if len(invalid) > 0 {
+		return &ValidationError{Err: ErrInvalidSeats, Fields: invalid}
+	}
+	return nil
+}
+
+// resolveVoucherCount picks how many vouchers to issue for a new assignment
+// and checks it against the aircraft's maximum.
+func resolveVoucherCount(aircraft model.AircraftInfo, requested int) (int, error) {
+	count := requested
+	if count == 0 {
+		count = aircraft.DefaultVouchers
+	}
+	if count == 0 {
+		count = defaultVoucherCount
+	}
+	if aircraft.MaxVouchers > 0 && count > aircraft.MaxVouchers {
+		return 0, fmt.Errorf("%w: %s allows at most %d vouchers per flight, %d requested", ErrTooManyVouchers, aircraft.Type, aircraft.MaxVouchers, count)
+	}
+	return count, nil
 }
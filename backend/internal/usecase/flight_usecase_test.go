@@ -3,25 +3,38 @@ package usecase
 import (
 	"bookcabin-voucher/internal/dto"
 	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/repository"
+	"bookcabin-voucher/internal/service"
 	"bookcabin-voucher/internal/utils"
 	mockRep "bookcabin-voucher/mocks/repository"
 	mockSvc "bookcabin-voucher/mocks/service"
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 	"testing"
 )
 
+const airbus320 model.AircraftType = "Airbus 320"
+
+// expectTx lets the usecase open a transaction that runs against repo itself
+// and lock the flight inside it.
+func expectTx(repo *mockRep.MockFlightRepository) {
+	repo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(repository.FlightRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().LockFlight(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+}
+
 func TestGenerateAndAssignSeats(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mockRep.NewMockFlightRepository(ctrl)
 	mockGen := mockSvc.NewMockSeatAllocator(ctrl)
-	uc := NewFlightUsecase(mockRepo, mockGen)
+	uc := NewFlightUsecase(mockRepo, mockGen, nil)
 
 	req := dto.GenerateRequest{
 		CrewName:      "ApArki",
@@ -36,15 +49,29 @@ func TestGenerateAndAssignSeats(t *testing.T) {
 		Seat: "3B",
 	}, {Seat: "7C"}, {Seat: "14D"}}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
-
-	tx := db.Begin()
-	mockRepo.EXPECT().BeginTx().Return(tx)
-	mockRepo.EXPECT().CountByFlightAndDateTx(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
-	mockGen.EXPECT().GenerateSeats(model.Airbus320, 3, make([]string, 0)).Return([]string{"3B", "7C", "14D"}, nil)
-	mockRepo.EXPECT().BulkCreateSeatAssignmentsTx(gomock.Any(), gomock.Any()).Return(nil)
-	mockRepo.EXPECT().GetByFilter(dto.FlightFilter{
+	mockGen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(mockRepo)
+	mockRepo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	mockRepo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	mockGen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, make([]string, 0), "").Return([]string{"3B", "7C", "14D"}, nil)
+	mockRepo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rows []model.FlightSeatAssignment) error {
+		codes := make(map[string]bool)
+		for _, row := range rows {
+			_, ok := service.NormalizeVoucherCode(row.Code)
+			assert.True(t, ok, "seat %s has code %q", row.Seat, row.Code)
+			codes[row.Code] = true
+		}
+		assert.Len(t, codes, 3)
+		return nil
+	})
+	mockRepo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B", "7C", "14D"}, model.SeatIssued).Return(nil)
+	mockRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		assert.Equal(t, model.EventCreated, e.Type)
+		assert.Equal(t, []string{"3B", "7C", "14D"}, e.SeatsTo)
+		assert.Equal(t, "270123", e.Actor) // no X-Actor, so the crew member
+		return nil
+	})
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{
 		FlightNumber: "JT692", Date: "26-07-25",
 	}).Return([]model.FlightAssignment{{SeatAssignments: seats}}, nil)
 
@@ -56,9 +83,9 @@ func TestGenerateAndAssignSeats(t *testing.T) {
 		AircraftType:    req.Aircraft,
 		SeatAssignments: seats,
 	}
-	mockRepo.EXPECT().CreateTx(gomock.Any(), gomock.Any()).Return(expectResp, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expectResp, nil)
 
-	result, err := uc.GenerateAndAssignSeats(req)
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(result.SeatAssignments))
@@ -67,13 +94,66 @@ func TestGenerateAndAssignSeats(t *testing.T) {
 	assert.Equal(t, []string{"3B", "7C", "14D"}, expectedSeats)
 }
 
+func TestGenerateAndAssignSeats_SignsVouchers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	mockGen := mockSvc.NewMockSeatAllocator(ctrl)
+	mockSigner := mockSvc.NewMockVoucherSigner(ctrl)
+	uc := NewFlightUsecase(mockRepo, mockGen, mockSigner)
+
+	req := dto.GenerateRequest{
+		CrewName:     "ApArki",
+		CrewID:       "270123",
+		FlightNumber: "JT692",
+		Date:         "26-07-25",
+		Aircraft:     "Airbus 320",
+	}
+	stored := model.FlightAssignment{
+		CrewName:     req.CrewName,
+		CrewID:       req.CrewID,
+		FlightNumber: req.FlightNumber,
+		FlightDate:   req.Date,
+		AircraftType: airbus320,
+		SeatAssignments: []model.FlightSeatAssignment{
+			{Seat: "3B", Code: "R7X6-5JZ3-03J1"},
+			{Seat: "7C", Code: "YDDR-7J6A-0XGG"},
+		},
+	}
+
+	mockGen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(mockRepo)
+	mockRepo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	mockRepo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	mockGen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, gomock.Any(), "").Return([]string{"3B", "7C"}, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&stored, nil)
+	mockRepo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B", "7C"}, model.SeatIssued).Return(nil)
+	mockRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{stored}, nil)
+
+	mockSigner.EXPECT().Sign(gomock.Any()).DoAndReturn(func(v model.Voucher) (string, error) {
+		assert.Equal(t, "270123", v.CrewID)
+		assert.Equal(t, "JT692", v.FlightNumber)
+		assert.Equal(t, "26-07-25", v.FlightDate)
+		return "token-" + v.Seat + "-" + v.Code, nil
+	}).Times(2)
+
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "token-3B-R7X6-5JZ3-03J1", result.SeatAssignments[0].Token)
+	assert.Equal(t, "token-7C-YDDR-7J6A-0XGG", result.SeatAssignments[1].Token)
+}
+
 func TestGenerateAndChangeSeats(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mockRep.NewMockFlightRepository(ctrl)
 	mockGen := mockSvc.NewMockSeatAllocator(ctrl)
-	uc := NewFlightUsecase(mockRepo, mockGen)
+	uc := NewFlightUsecase(mockRepo, mockGen, nil)
 
 	req := dto.GenerateRequest{
 		CrewName:      "ApArki",
@@ -84,28 +164,46 @@ func TestGenerateAndChangeSeats(t *testing.T) {
 		SeatsToChange: []string{"14D"},
 	}
 
-	seatsToChange := []model.FlightSeatAssignment{{Seat: "14D"}}
+	stored := model.FlightAssignment{
+		ID: 1, CrewName: "ApArki", CrewID: "270123", AircraftType: airbus320,
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}, {Seat: "7C"}, {Seat: "14D"}},
+	}
 	seats := []model.FlightSeatAssignment{{
 		Seat: "3B",
 	}, {Seat: "7C"}, {Seat: "12A"}}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
-
-	tx := db.Begin()
-	mockRepo.EXPECT().BeginTx().Return(tx)
-	mockRepo.EXPECT().CountByFlightAndDateTx(gomock.Any(), "JT692", "26-07-25").Return(int64(1))
-	mockGen.EXPECT().GenerateSeats(model.Airbus320, 1, []string{"14D"}).Return([]string{"12A"}, nil)
-	mockRepo.EXPECT().GetByFilterTx(gomock.Any(), dto.FlightFilter{
+	mockGen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(mockRepo)
+	mockRepo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(1))
+	mockRepo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{"3B", "7C", "14D"}, nil)
+	mockGen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 1, []string{"3B", "7C", "14D"}, "").Return([]string{"12A"}, nil)
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{
+		FlightNumber: "JT692", Date: "26-07-25",
+	}).Return([]model.FlightAssignment{stored}, nil)
+	mockRepo.EXPECT().DeleteSeatsByFilter(gomock.Any(), dto.FlightFilter{
 		FlightNumber: "JT692", Date: "26-07-25", Seats: []string{"14D"},
-	}).Return([]model.FlightAssignment{{SeatAssignments: seatsToChange}}, nil)
-	mockRepo.EXPECT().DeleteSeatsByFilterTx(gomock.Any(), gomock.Any()).Return(int64(1), nil)
-	mockRepo.EXPECT().BulkCreateSeatAssignmentsTx(gomock.Any(), gomock.Any()).Return(nil)
-	mockRepo.EXPECT().GetByFilter(dto.FlightFilter{
+	}, "270123").Return(int64(1), nil)
+	mockRepo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rows []model.FlightSeatAssignment) error {
+		// the replacement seat gets a code of its own
+		require.Len(t, rows, 1)
+		assert.Equal(t, "12A", rows[0].Seat)
+		_, ok := service.NormalizeVoucherCode(rows[0].Code)
+		assert.True(t, ok, rows[0].Code)
+		return nil
+	})
+	mockRepo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"14D"}, model.SeatFree).Return(nil)
+	mockRepo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"12A"}, model.SeatIssued).Return(nil)
+	mockRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		assert.Equal(t, model.EventReRolled, e.Type)
+		assert.Equal(t, []string{"14D"}, e.SeatsFrom)
+		assert.Equal(t, []string{"12A"}, e.SeatsTo)
+		return nil
+	})
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{
 		FlightNumber: "JT692", Date: "26-07-25",
 	}).Return([]model.FlightAssignment{{SeatAssignments: seats}}, nil)
 
-	result, err := uc.GenerateAndAssignSeats(req)
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(result.SeatAssignments))
@@ -120,7 +218,7 @@ func TestGenerateAndAssignSeats_FlightExists(t *testing.T) {
 
 	repo := mockRep.NewMockFlightRepository(ctrl)
 	gen := mockSvc.NewMockSeatAllocator(ctrl)
-	uc := NewFlightUsecase(repo, gen)
+	uc := NewFlightUsecase(repo, gen, nil)
 
 	req := dto.GenerateRequest{
 		CrewName:      "ApArki",
@@ -131,18 +229,16 @@ func TestGenerateAndAssignSeats_FlightExists(t *testing.T) {
 		SeatsToChange: make([]string, 0),
 	}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
-
-	tx := db.Begin()
-	repo.EXPECT().BeginTx().Return(tx)
-	repo.EXPECT().CountByFlightAndDateTx(gomock.Any(), "JT692", "26-07-25").Return(int64(1))
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(1))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
 
-	result, err := uc.GenerateAndAssignSeats(req)
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "assignment for this flight and date already exists and no seats to change")
+	assert.ErrorIs(t, err, ErrAssignmentExists)
 }
 
 func TestGenerateAndAssignSeats_SeatGenerationFailed(t *testing.T) {
@@ -151,7 +247,7 @@ func TestGenerateAndAssignSeats_SeatGenerationFailed(t *testing.T) {
 
 	repo := mockRep.NewMockFlightRepository(ctrl)
 	gen := mockSvc.NewMockSeatAllocator(ctrl)
-	uc := NewFlightUsecase(repo, gen)
+	uc := NewFlightUsecase(repo, gen, nil)
 
 	req := dto.GenerateRequest{
 		CrewName:      "ApArki",
@@ -162,19 +258,54 @@ func TestGenerateAndAssignSeats_SeatGenerationFailed(t *testing.T) {
 		SeatsToChange: make([]string, 0),
 	}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
-
-	tx := db.Begin()
-	repo.EXPECT().BeginTx().Return(tx)
-	repo.EXPECT().CountByFlightAndDateTx(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
-	gen.EXPECT().GenerateSeats(model.Airbus320, 3, make([]string, 0)).Return(nil, errors.New("unknown aircraft"))
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, make([]string, 0), "").Return(nil, service.ErrNotEnoughSeats)
 
-	result, err := uc.GenerateAndAssignSeats(req)
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "failed to generate seats")
+	assert.ErrorIs(t, err, service.ErrNotEnoughSeats)
+}
+
+func TestGenerateAndAssignSeats_ReadBackMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	req := dto.GenerateRequest{
+		CrewName:      "ApArki",
+		CrewID:        "270123",
+		FlightNumber:  "JT692",
+		Date:          "26-07-25",
+		Aircraft:      "Airbus 320",
+		SeatsToChange: make([]string, 0),
+	}
+
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, make([]string, 0), "").Return([]string{"3B", "7C", "14D"}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&model.FlightAssignment{}, nil)
+	repo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", gomock.Any(), model.SeatIssued).Return(nil)
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+	// nothing comes back when the written assignment is read
+	repo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}).Return(nil, nil)
+
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrAssignmentNotFound)
 }
 
 func TestGenerateAndAssignSeats_DBCreateFailed(t *testing.T) {
@@ -183,7 +314,7 @@ func TestGenerateAndAssignSeats_DBCreateFailed(t *testing.T) {
 
 	repo := mockRep.NewMockFlightRepository(ctrl)
 	gen := mockSvc.NewMockSeatAllocator(ctrl)
-	uc := NewFlightUsecase(repo, gen)
+	uc := NewFlightUsecase(repo, gen, nil)
 
 	req := dto.GenerateRequest{
 		CrewName:     "ApArki",
@@ -195,18 +326,472 @@ func TestGenerateAndAssignSeats_DBCreateFailed(t *testing.T) {
 
 	seats := []string{"3B", "7C", "14D"}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
-
-	tx := db.Begin()
-	repo.EXPECT().BeginTx().Return(tx)
-	repo.EXPECT().CountByFlightAndDateTx(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
-	gen.EXPECT().GenerateSeats(model.Airbus320, 3, make([]string, 0)).Return(seats, nil)
-	repo.EXPECT().CreateTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to create in DB"))
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, make([]string, 0), "").Return(seats, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to create in DB"))
 
-	result, err := uc.GenerateAndAssignSeats(req)
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "failed to create in DB")
 }
+
+func TestGenerateAndAssignSeats_ResolvesAlias(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	req := dto.GenerateRequest{
+		CrewName:     "ApArki",
+		CrewID:       "270123",
+		FlightNumber: "JT692",
+		Date:         "26-07-25",
+		Aircraft:     "A320",
+	}
+
+	gen.EXPECT().Resolve("A320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+	gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, make([]string, 0), "").Return([]string{"3B", "7C", "14D"}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
+			assert.Equal(t, airbus320, assignment.AircraftType)
+			return assignment, nil
+		})
+	repo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", gomock.Any(), model.SeatIssued).Return(nil)
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{AircraftType: airbus320}}, nil)
+
+	result, err := uc.GenerateAndAssignSeats(t.Context(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, airbus320, result.AircraftType)
+}
+
+func TestGenerateAndAssignSeats_UnknownAircraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	gen.EXPECT().Resolve("Concorde").Return(model.AircraftInfo{}, false)
+
+	result, err := uc.GenerateAndAssignSeats(t.Context(), dto.GenerateRequest{FlightNumber: "JT692", Date: "26-07-25", Aircraft: "Concorde"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrUnknownAircraft)
+}
+
+func TestGenerateAndAssignSeats_AvoidsSeatsIssuedOnFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	req := dto.GenerateRequest{
+		CrewName:     "ApArki",
+		CrewID:       "270123",
+		FlightNumber: "JT692",
+		Date:         "26-07-25",
+		Aircraft:     "Airbus 320",
+	}
+
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320}, true)
+	expectTx(repo)
+	repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+	repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{"1A", "1C"}, nil)
+	gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, 3, []string{"1A", "1C"}, "").Return([]string{"3B", "7C", "14D"}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
+			return assignment, nil
+		})
+	repo.EXPECT().BulkCreateSeatAssignments(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B", "7C", "14D"}, model.SeatIssued).Return(nil)
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{}}, nil)
+
+	_, err := uc.GenerateAndAssignSeats(t.Context(), req)
+
+	assert.NoError(t, err)
+}
+
+func TestGetSeatInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	gen.EXPECT().Resolve("").Return(model.AircraftInfo{}, false)
+	repo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}).
+		Return([]model.FlightAssignment{{AircraftType: airbus320}}, nil)
+	gen.EXPECT().Resolve("Airbus 320").Return(model.AircraftInfo{Type: airbus320, Seats: 180}, true)
+	repo.EXPECT().GetSeatInventory(gomock.Any(), "JT692", "26-07-25").Return([]model.FlightSeatInventory{
+		{Seat: "1A", Status: model.SeatIssued},
+		{Seat: "2B", Status: model.SeatFree},
+	}, nil)
+
+	inventory, err := uc.GetSeatInventory(t.Context(), dto.SeatInventoryRequest{FlightNumber: "JT692", Date: "26-07-25"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, airbus320, inventory.Aircraft)
+	assert.Equal(t, 180, inventory.Capacity)
+	assert.Equal(t, map[model.SeatStatus]int{
+		model.SeatFree: 179, model.SeatIssued: 1,
+	}, inventory.Counts)
+}
+
+func TestGetSeatInventory_AircraftRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	gen.EXPECT().Resolve("").Return(model.AircraftInfo{}, false)
+	repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	inventory, err := uc.GetSeatInventory(t.Context(), dto.SeatInventoryRequest{FlightNumber: "JT692", Date: "26-07-25"})
+
+	assert.ErrorIs(t, err, ErrAircraftRequired)
+	assert.Nil(t, inventory)
+}
+
+func TestGenerateAndAssignSeats_VoucherCount(t *testing.T) {
+	limited := model.AircraftInfo{Type: airbus320, DefaultVouchers: 4, MaxVouchers: 6}
+
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+	}{
+		{name: "aircraft default", requested: 0, want: 4},
+		{name: "explicit", requested: 6, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockRep.NewMockFlightRepository(ctrl)
+			gen := mockSvc.NewMockSeatAllocator(ctrl)
+			uc := NewFlightUsecase(repo, gen, nil)
+
+			gen.EXPECT().Resolve("Airbus 320").Return(limited, true)
+			expectTx(repo)
+			repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(0))
+			repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{}, nil)
+			gen.EXPECT().GenerateSeats(gomock.Any(), airbus320, tt.want, []string{}, "").Return(nil, errors.New("stop here"))
+
+			_, err := uc.GenerateAndAssignSeats(t.Context(), dto.GenerateRequest{
+				FlightNumber: "JT692", Date: "26-07-25", Aircraft: "Airbus 320", Count: tt.requested,
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerateAndAssignSeats_VoucherCountAboveMaximum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	gen.EXPECT().Resolve("ATR").Return(model.AircraftInfo{Type: "ATR", MaxVouchers: 4}, true)
+
+	result, err := uc.GenerateAndAssignSeats(t.Context(), dto.GenerateRequest{
+		FlightNumber: "JT692", Date: "26-07-25", Aircraft: "ATR", Count: 5,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrTooManyVouchers)
+	assert.Contains(t, err.Error(), "at most 4 vouchers")
+}
+
+func TestGetAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	filter := dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}
+	repo.EXPECT().GetByFilter(gomock.Any(), filter).Return([]model.FlightAssignment{{CrewID: "270123"}}, nil)
+	repo.EXPECT().GetByFilter(gomock.Any(), filter).Return(nil, nil)
+
+	assignment, err := uc.GetAssignment(t.Context(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"})
+	assert.NoError(t, err)
+	assert.Equal(t, "270123", assignment.CrewID)
+
+	assignment, err = uc.GetAssignment(t.Context(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"})
+	assert.ErrorIs(t, err, ErrAssignmentNotFound)
+	assert.Nil(t, assignment)
+}
+
+func TestListAssignments_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	gen.EXPECT().Resolve("A320").Return(model.AircraftInfo{Type: airbus320}, true).Times(2)
+	repo.EXPECT().ListAssignments(gomock.Any(), dto.AssignmentFilter{
+		FromDay:            "2025-07-01",
+		ToDay:              "2025-07-31",
+		FlightNumberPrefix: []string{"JT"},
+		Aircraft:           airbus320,
+		Sort:               dto.SortByFlightDate,
+		Limit:              3,
+	}).Return([]model.FlightAssignment{
+		{ID: 4, FlightDay: "2025-07-02"},
+		{ID: 2, FlightDay: "2025-07-05"},
+		{ID: 7, FlightDay: "2025-07-09"},
+	}, nil)
+
+	req := dto.ListAssignmentsRequest{
+		From: "01-07-25", To: "31-07-25", Airline: "JT", Aircraft: "A320", Sort: "flightDate", Limit: 2,
+	}
+	page, cursor, err := uc.ListAssignments(t.Context(), req)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	require.NotEmpty(t, cursor)
+
+	repo.EXPECT().ListAssignments(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter dto.AssignmentFilter) ([]model.FlightAssignment, error) {
+		assert.Equal(t, &dto.AssignmentCursor{Value: "2025-07-05", ID: 2}, filter.After)
+		return []model.FlightAssignment{{ID: 7, FlightDay: "2025-07-09"}}, nil
+	})
+
+	req.Cursor = cursor
+	page, cursor, err = uc.ListAssignments(t.Context(), req)
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Empty(t, cursor)
+}
+
+func TestListAssignments_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	repo.EXPECT().ListAssignments(gomock.Any(), dto.AssignmentFilter{
+		CrewID:     "270123",
+		Sort:       dto.SortByCreatedAt,
+		Descending: true,
+		Limit:      21,
+	}).Return(nil, nil)
+
+	page, cursor, err := uc.ListAssignments(t.Context(), dto.ListAssignmentsRequest{CrewID: "270123"})
+	assert.NoError(t, err)
+	assert.Empty(t, page)
+	assert.Empty(t, cursor)
+}
+
+func TestListAssignments_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	_, _, err := uc.ListAssignments(t.Context(), dto.ListAssignmentsRequest{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	// a cursor issued for one sort order cannot be replayed against another
+	other := encodeCursor(dto.AssignmentCursor{Value: "JT692", ID: 3}, "flightNumber")
+	_, _, err = uc.ListAssignments(t.Context(), dto.ListAssignmentsRequest{Cursor: other, Sort: "-flightNumber"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestRevokeAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	flight := dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}
+	revoke := dto.RevokeRequest{Actor: "ops", Reason: "flight cancelled"}
+
+	expectTx(repo)
+	repo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}).
+		Return([]model.FlightAssignment{{ID: 5, SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}, {Seat: "7C"}}}}, nil)
+	repo.EXPECT().RevokeAssignment(gomock.Any(), uint(5), "ops", "flight cancelled").Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B", "7C"}, model.SeatFree).Return(nil)
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		assert.Equal(t, model.EventRevoked, e.Type)
+		assert.Equal(t, []string{"3B", "7C"}, e.SeatsFrom)
+		assert.Equal(t, "flight cancelled", e.Reason)
+		return nil
+	})
+
+	assert.NoError(t, uc.RevokeAssignment(t.Context(), flight, revoke))
+
+	expectTx(repo)
+	repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	assert.ErrorIs(t, uc.RevokeAssignment(t.Context(), flight, revoke), ErrAssignmentNotFound)
+}
+
+func TestRevokeSeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	flight := dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}
+	filter := dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}
+	revoke := dto.RevokeRequest{Actor: "ops", Reason: "crew change"}
+	held := []model.FlightAssignment{{ID: 5, SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}, {Seat: "7C"}}}}
+
+	expectTx(repo)
+	repo.EXPECT().GetByFilter(gomock.Any(), filter).Return(held, nil)
+	repo.EXPECT().RevokeSeats(gomock.Any(), uint(5), []string{"3B"}, "ops", "crew change").Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B"}, model.SeatFree).Return(nil)
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		assert.Equal(t, model.EventSeatRevoked, e.Type)
+		assert.Equal(t, []string{"3B"}, e.SeatsFrom)
+		assert.Equal(t, "ops", e.Actor)
+		return nil
+	})
+	repo.EXPECT().GetByFilter(gomock.Any(), filter).Return([]model.FlightAssignment{{ID: 5, SeatAssignments: []model.FlightSeatAssignment{{Seat: "7C"}}}}, nil)
+
+	assignment, err := uc.RevokeSeat(t.Context(), dto.SeatPathRequest{FlightPathRequest: flight, Seat: "3B"}, revoke)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7C"}, utils.ExtractSeats(assignment.SeatAssignments))
+
+	expectTx(repo)
+	repo.EXPECT().GetByFilter(gomock.Any(), filter).Return(held, nil)
+
+	_, err = uc.RevokeSeat(t.Context(), dto.SeatPathRequest{FlightPathRequest: flight, Seat: "9A"}, revoke)
+	assert.ErrorIs(t, err, ErrSeatNotAssigned)
+}
+
+func TestRevokeSeat_LastSeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewFlightUsecase(repo, mockSvc.NewMockSeatAllocator(ctrl), nil)
+
+	flight := dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}
+	revoke := dto.RevokeRequest{Actor: "ops", Reason: "crew change"}
+
+	expectTx(repo)
+	repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{ID: 5, SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}}}}, nil)
+	repo.EXPECT().RevokeSeats(gomock.Any(), uint(5), []string{"3B"}, "ops", "crew change").Return(nil)
+	repo.EXPECT().SetSeatStatus(gomock.Any(), "JT692", "26-07-25", []string{"3B"}, model.SeatFree).Return(nil)
+	// nothing is left, so the assignment goes too and the flight can be generated again
+	repo.EXPECT().RevokeAssignment(gomock.Any(), uint(5), "ops", "crew change").Return(nil)
+	var events []model.AssignmentEventType
+	repo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		events = append(events, e.Type)
+		return nil
+	}).Times(2)
+
+	assignment, err := uc.RevokeSeat(t.Context(), dto.SeatPathRequest{FlightPathRequest: flight, Seat: "3B"}, revoke)
+	require.NoError(t, err)
+	assert.Nil(t, assignment)
+	assert.Equal(t, []model.AssignmentEventType{model.EventSeatRevoked, model.EventRevoked}, events)
+}
+
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockRep.NewMockFlightRepository(ctrl)
+	gen := mockSvc.NewMockSeatAllocator(ctrl)
+	uc := NewFlightUsecase(repo, gen, nil)
+
+	flight := dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}
+	repo.EXPECT().ListEvents(gomock.Any(), "JT692", "26-07-25").Return([]model.FlightAssignmentEvent{{Type: model.EventCreated}}, nil)
+	repo.EXPECT().ListEvents(gomock.Any(), "JT692", "26-07-25").Return(nil, nil)
+
+	events, err := uc.GetHistory(t.Context(), flight)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	_, err = uc.GetHistory(t.Context(), flight)
+	assert.ErrorIs(t, err, ErrAssignmentNotFound)
+}
+
+func TestGenerateAndChangeSeats_RejectsInvalidReRoll(t *testing.T) {
+	stored := model.FlightAssignment{
+		ID: 1, CrewName: "ApArki", CrewID: "270123", AircraftType: airbus320,
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}, {Seat: "7C"}, {Seat: "14D"}},
+	}
+	base := dto.GenerateRequest{
+		CrewName: "apArki ", CrewID: "270123", FlightNumber: "JT692", Date: "26-07-25", Aircraft: "Airbus 320",
+	}
+
+	tests := []struct {
+		name     string
+		change   func(r *dto.GenerateRequest)
+		resolved model.AircraftType
+		want     error
+		fields   []string
+	}{
+		{"seat not held", func(r *dto.GenerateRequest) { r.SeatsToChange = []string{"3B", "9A"} }, airbus320, ErrInvalidSeats, []string{"seats[1]"}},
+		{"duplicate seat", func(r *dto.GenerateRequest) { r.SeatsToChange = []string{"3B", "3B"} }, airbus320, ErrInvalidSeats, []string{"seats[1]"}},
+		{"other crew", func(r *dto.GenerateRequest) { r.SeatsToChange = []string{"3B"}; r.CrewID = "999"; r.CrewName = "Sarah" }, airbus320, ErrAssignmentMismatch, []string{"id", "name"}},
+		{"other aircraft", func(r *dto.GenerateRequest) { r.SeatsToChange = []string{"3B"}; r.Aircraft = "ATR" }, "ATR", ErrAssignmentMismatch, []string{"aircraft"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockRep.NewMockFlightRepository(ctrl)
+			gen := mockSvc.NewMockSeatAllocator(ctrl)
+			uc := NewFlightUsecase(repo, gen, nil)
+
+			req := base
+			tt.change(&req)
+			gen.EXPECT().Resolve(string(req.Aircraft)).Return(model.AircraftInfo{Type: tt.resolved}, true)
+			expectTx(repo)
+			repo.EXPECT().CountByFlightAndDate(gomock.Any(), "JT692", "26-07-25").Return(int64(1))
+			repo.EXPECT().FindOccupiedSeats(gomock.Any(), "JT692", "26-07-25").Return([]string{"3B", "7C", "14D"}, nil)
+			repo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{stored}, nil)
+
+			result, err := uc.GenerateAndAssignSeats(t.Context(), req)
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tt.want)
+
+			var invalid *ValidationError
+			require.ErrorAs(t, err, &invalid)
+			fields := make([]string, 0, len(invalid.Fields))
+			for _, f := range invalid.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
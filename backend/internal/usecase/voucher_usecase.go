@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/model"
+	"context"
+	"io"
+)
+
+type VoucherUsecase interface {
+	// RenderVouchers writes a printable voucher for every seat of the flight's assignment to w.
+	RenderVouchers(ctx context.Context, request dto.FlightPathRequest, w io.Writer) error
+	// RenderSeatQR writes a QR code of one seat's voucher to w, holding its code, signed token or boarding pass.
+	RenderSeatQR(ctx context.Context, seat dto.SeatPathRequest, request dto.SeatQRRequest, w io.Writer) error
+	// GetVoucher looks a voucher up by code, whether it is live, used or revoked.
+	GetVoucher(ctx context.Context, request dto.VoucherPathRequest) (*model.IssuedVoucher, error)
+	// RedeemVoucher marks a live voucher as used at a station, once the flight, date and seat match.
+	RedeemVoucher(ctx context.Context, voucher dto.VoucherPathRequest, request dto.RedeemRequest) (*model.IssuedVoucher, error)
+	// VoucherKeys lists the public keys voucher tokens are verified with.
+	VoucherKeys() []model.VoucherKey
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/repository"
+	"bookcabin-voucher/internal/service"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+const defaultQRSize = 256 // pixels
+
+type voucherUsecaseImpl struct {
+	repo     repository.FlightRepository
+	renderer service.VoucherRenderer
+	signer   service.VoucherSigner // nil leaves the printed vouchers without a token
+}
+
+func NewVoucherUsecase(repo repository.FlightRepository, renderer service.VoucherRenderer, signer service.VoucherSigner) VoucherUsecase {
+	return &voucherUsecaseImpl{
+		repo:     repo,
+		renderer: renderer,
+		signer:   signer,
+	}
+}
+
+func (u *voucherUsecaseImpl) RenderVouchers(ctx context.Context, request dto.FlightPathRequest, w io.Writer) error {
+	assignment, err := findAssignment(ctx, u.repo, request)
+	if err != nil {
+		return err
+	}
+	if len(assignment.SeatAssignments) == 0 {
+		return ErrAssignmentNotFound
+	}
+
+	vouchers := make([]model.Voucher, 0, len(assignment.SeatAssignments))
+	for _, seat := range assignment.SeatAssignments {
+		voucher, err := u.printableVoucher(assignment, seat)
+		if err != nil {
+			return err
+		}
+		vouchers = append(vouchers, voucher)
+	}
+	if err := u.renderer.Render(w, vouchers); err != nil {
+		log.Printf("[Usecase] Failed to render vouchers for %s on %s: %v", request.FlightNumber, request.Date, err)
+		return err
+	}
+	return nil
+}
+
+func (u *voucherUsecaseImpl) RenderSeatQR(ctx context.Context, path dto.SeatPathRequest, request dto.SeatQRRequest, w io.Writer) error {
+	assignment, err := findAssignment(ctx, u.repo, path.FlightPathRequest)
+	if err != nil {
+		return err
+	}
+	for _, seat := range assignment.SeatAssignments {
+		if seat.Seat != path.Seat {
+			continue
+		}
+		content, err := u.qrContent(newVoucher(assignment, seat), request)
+		if err != nil {
+			return err
+		}
+		code, err := service.EncodeQR(content)
+		if err != nil {
+			return err
+		}
+
+		format, size := request.Format, request.Size
+		if format == "" {
+			format = service.QRFormatPNG
+		}
+		if size == 0 {
+			size = defaultQRSize
+		}
+		return code.Write(w, format, size)
+	}
+	return ErrSeatNotAssigned
+}
+
+// qrContent is what the QR code of a voucher holds for the requested payload.
+func (u *voucherUsecaseImpl) qrContent(voucher model.Voucher, request dto.SeatQRRequest) (string, error) {
+	switch request.Payload {
+	case model.QRPayloadToken:
+		if u.signer == nil {
+			return "", ErrTokenSigningDisabled
+		}
+		return u.signer.Sign(voucher)
+	case model.QRPayloadBCBP:
+		return service.EncodeBCBP(voucher, request.From, request.To)
+	default:
+		if voucher.Code == "" {
+			return "", fmt.Errorf("%w: seat %s was issued before voucher codes", ErrVoucherNotFound, voucher.Seat)
+		}
+		return voucher.Code, nil
+	}
+}
+
+// printableVoucher describes a seat for the PDF, with its token and boarding pass.
+func (u *voucherUsecaseImpl) printableVoucher(assignment *model.FlightAssignment, seat model.FlightSeatAssignment) (model.Voucher, error) {
+	voucher := newVoucher(assignment, seat)
+	bcbp, err := service.EncodeBCBP(voucher, "", "")
+	if err != nil {
+		return voucher, err
+	}
+	voucher.BCBP = bcbp
+	if u.signer != nil {
+		if voucher.Token, err = u.signer.Sign(voucher); err != nil {
+			return voucher, err
+		}
+	}
+	return voucher, nil
+}
+
+func (u *voucherUsecaseImpl) VoucherKeys() []model.VoucherKey {
+	// vouchers carry no token without a signer, so there is nothing to verify
+	if u.signer == nil {
+		return nil
+	}
+	return u.signer.PublicKeys()
+}
+
+func (u *voucherUsecaseImpl) GetVoucher(ctx context.Context, request dto.VoucherPathRequest) (*model.IssuedVoucher, error) {
+	return findVoucher(ctx, u.repo, request.Code)
+}
+
+func (u *voucherUsecaseImpl) RedeemVoucher(ctx context.Context, voucher dto.VoucherPathRequest, request dto.RedeemRequest) (*model.IssuedVoucher, error) {
+	err := u.repo.WithinTx(ctx, func(repo repository.FlightRepository) error {
+		// a revocation of the seat waits for the redemption, and the other way round
+		if err := repo.LockFlight(ctx, request.FlightNumber, request.Date); err != nil {
+			return err
+		}
+		issued, err := findVoucher(ctx, repo, voucher.Code)
+		if err != nil {
+			return err
+		}
+		if err := checkRedemption(request, issued); err != nil {
+			log.Printf("[Usecase] Rejected redemption of %s: %v", issued.Seat.Code, err)
+			return err
+		}
+
+		if err := repo.RedeemVoucher(ctx, &model.VoucherRedemption{
+			FlightSeatAssignmentID: issued.Seat.ID,
+			Code:                   issued.Seat.Code,
+			Station:                request.Station,
+			RedeemedBy:             request.Actor,
+		}); err != nil {
+			log.Printf("[Usecase] Failed to redeem voucher %s: %v", issued.Seat.Code, err)
+			return err
+		}
+
+		meta := request.Meta
+		meta.Actor = request.Actor
+		event := newEvent(issued.Assignment.ID, request.FlightNumber, request.Date, model.EventRedeemed, meta)
+		event.Reason = fmt.Sprintf("voucher %s for seat %s redeemed at %s", issued.Seat.Code, issued.Seat.Seat, request.Station)
+		if err := repo.AppendEvent(ctx, event); err != nil {
+			log.Printf("[Usecase] Failed to record event for %s: %v", request.FlightNumber, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, concurrentChange(err)
+	}
+	log.Printf("[Usecase] Voucher for seat %s on %s %s redeemed at %s", request.Seat, request.FlightNumber, request.Date, request.Station)
+
+	return findVoucher(ctx, u.repo, voucher.Code)
+}
+
+// newVoucher describes one seat of an assignment as issued to the crew member.
+func newVoucher(assignment *model.FlightAssignment, seat model.FlightSeatAssignment) model.Voucher {
+	return model.Voucher{
+		Code:         seat.Code,
+		CrewName:     assignment.CrewName,
+		CrewID:       assignment.CrewID,
+		FlightNumber: assignment.FlightNumber,
+		FlightDate:   assignment.FlightDate,
+		Aircraft:     assignment.AircraftType,
+		Seat:         seat.Seat,
+		IssuedAt:     seat.CreatedAt,
+	}
+}
+
+// findVoucher loads a voucher by code through repo, accepting the code as typed.
+func findVoucher(ctx context.Context, repo repository.FlightRepository, code string) (*model.IssuedVoucher, error) {
+	normalized, ok := service.NormalizeVoucherCode(code)
+	if !ok {
+		return nil, ErrVoucherNotFound
+	}
+	voucher, err := repo.FindVoucher(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if voucher == nil {
+		return nil, ErrVoucherNotFound
+	}
+	return voucher, nil
+}
+
+// checkRedemption accepts a voucher presented for the flight, date and seat it
+// was issued for that has been neither used nor revoked.
+func checkRedemption(request dto.RedeemRequest, voucher *model.IssuedVoucher) error {
+	var mismatches []FieldError
+	if request.FlightNumber != voucher.Assignment.FlightNumber {
+		mismatches = append(mismatches, FieldError{Field: "flightNumber", Rule: "mismatch", Value: request.FlightNumber,
+			Message: "voucher was issued for another flight"})
+	}
+	if request.Date != voucher.Assignment.FlightDate {
+		mismatches = append(mismatches, FieldError{Field: "date", Rule: "mismatch", Value: request.Date,
+			Message: "voucher was issued for another date"})
+	}
+	if request.Seat != voucher.Seat.Seat {
+		mismatches = append(mismatches, FieldError{Field: "seat", Rule: "mismatch", Value: request.Seat,
+			Message: "voucher was issued for another seat"})
+	}
+	if len(mismatches) > 0 {
+		return &ValidationError{Err: ErrVoucherMismatch, Fields: mismatches}
+	}
+
+	switch voucher.State() {
+	case model.VoucherRedeemed:
+		return fmt.Errorf("%w at %s on %s", ErrVoucherRedeemed, voucher.Redemption.Station, voucher.Redemption.RedeemedAt.UTC().Format(time.RFC3339))
+	case model.VoucherRevoked:
+		return ErrVoucherRevoked
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"bookcabin-voucher/internal/dto"
+	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
+	mockRep "bookcabin-voucher/mocks/repository"
+	mockSvc "bookcabin-voucher/mocks/service"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRenderVouchers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	mockRenderer := mockSvc.NewMockVoucherRenderer(ctrl)
+	mockSigner := mockSvc.NewMockVoucherSigner(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockRenderer, mockSigner)
+
+	issued := time.Date(2025, 7, 20, 8, 30, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}).Return([]model.FlightAssignment{{
+		CrewName: "ApArki", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", AircraftType: "ATR",
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B", Code: "R7X6-5JZ3-03J1", CreatedAt: issued}, {Seat: "7C", Code: "YDDR-7J6A-0XGG", CreatedAt: issued}},
+	}}, nil)
+	mockSigner.EXPECT().Sign(gomock.Any()).DoAndReturn(func(v model.Voucher) (string, error) {
+		return "token-" + v.Seat, nil
+	}).Times(2)
+	mockRenderer.EXPECT().Render(gomock.Any(), []model.Voucher{
+		{Code: "R7X6-5JZ3-03J1", CrewName: "ApArki", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", Aircraft: "ATR", Seat: "3B", IssuedAt: issued,
+			Token: "token-3B", BCBP: "M1APARKI              E98123        JT 0692 207Y003B0000 10CR7X65JZ303J1"},
+		{Code: "YDDR-7J6A-0XGG", CrewName: "ApArki", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", Aircraft: "ATR", Seat: "7C", IssuedAt: issued,
+			Token: "token-7C", BCBP: "M1APARKI              E98123        JT 0692 207Y007C0000 10CYDDR7J6A0XGG"},
+	}).DoAndReturn(func(w io.Writer, _ []model.Voucher) error {
+		_, err := w.Write([]byte("%PDF-"))
+		return err
+	})
+
+	var out bytes.Buffer
+	err := uc.RenderVouchers(t.Context(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF-", out.String())
+}
+
+func TestRenderVouchers_NoAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	err := uc.RenderVouchers(t.Context(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, io.Discard)
+
+	assert.ErrorIs(t, err, ErrAssignmentNotFound)
+}
+
+func TestRenderVouchers_RenderError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	mockRenderer := mockSvc.NewMockVoucherRenderer(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockRenderer, nil)
+
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{
+		FlightNumber: "JT692", FlightDate: "26-07-25", SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B"}},
+	}}, nil)
+	mockRenderer.EXPECT().Render(gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+
+	err := uc.RenderVouchers(t.Context(), dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, io.Discard)
+
+	assert.EqualError(t, err, "disk full")
+}
+
+// expectQR encodes content the way RenderSeatQR should have drawn it.
+func expectQR(t *testing.T, content, format string, size int) []byte {
+	code, err := service.EncodeQR(content)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, code.Write(&out, format, size))
+	return out.Bytes()
+}
+
+func TestRenderSeatQR(t *testing.T) {
+	seat := dto.SeatPathRequest{FlightPathRequest: dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, Seat: "7C"}
+	assignment := model.FlightAssignment{
+		CrewName: "Ann Lee", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", AircraftType: "ATR",
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B", Code: "R7X6-5JZ3-03J1"}, {Seat: "7C", Code: "YDDR-7J6A-0XGG"}},
+	}
+
+	tests := map[string]struct {
+		request dto.SeatQRRequest
+		want    []byte
+	}{
+		"code as png": {
+			request: dto.SeatQRRequest{},
+			want:    expectQR(t, "YDDR-7J6A-0XGG", service.QRFormatPNG, 256),
+		},
+		"token as svg": {
+			request: dto.SeatQRRequest{Payload: model.QRPayloadToken, Format: service.QRFormatSVG, Size: 512},
+			want:    expectQR(t, "token-7C", service.QRFormatSVG, 512),
+		},
+		"boarding pass": {
+			request: dto.SeatQRRequest{Payload: model.QRPayloadBCBP, From: "CGK", To: "DPS"},
+			want:    expectQR(t, "M1LEE/ANN             E98123  CGKDPSJT 0692 207Y007C0000 10CYDDR7J6A0XGG", service.QRFormatPNG, 256),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockRep.NewMockFlightRepository(ctrl)
+			mockSigner := mockSvc.NewMockVoucherSigner(ctrl)
+			uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), mockSigner)
+
+			mockRepo.EXPECT().GetByFilter(gomock.Any(), dto.FlightFilter{FlightNumber: "JT692", Date: "26-07-25"}).Return([]model.FlightAssignment{assignment}, nil)
+			mockSigner.EXPECT().Sign(gomock.Any()).DoAndReturn(func(v model.Voucher) (string, error) {
+				return "token-" + v.Seat, nil
+			}).AnyTimes()
+
+			var out bytes.Buffer
+			err := uc.RenderSeatQR(t.Context(), seat, tt.request, &out)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.Bytes())
+		})
+	}
+}
+
+func TestRenderSeatQR_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{
+		FlightNumber: "JT692", FlightDate: "26-07-25",
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B", Code: "R7X6-5JZ3-03J1"}, {Seat: "7C"}},
+	}}, nil).Times(2)
+
+	path := dto.SeatPathRequest{FlightPathRequest: dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}}
+
+	path.Seat = "9A"
+	err := uc.RenderSeatQR(t.Context(), path, dto.SeatQRRequest{}, io.Discard)
+	assert.ErrorIs(t, err, ErrSeatNotAssigned)
+
+	// seats issued before voucher codes have no code to show
+	path.Seat = "7C"
+	err = uc.RenderSeatQR(t.Context(), path, dto.SeatQRRequest{}, io.Discard)
+	assert.ErrorIs(t, err, ErrVoucherNotFound)
+}
+
+func TestRenderSeatQR_TokenWithoutSigner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+	mockRepo.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]model.FlightAssignment{{
+		FlightNumber: "JT692", FlightDate: "26-07-25",
+		SeatAssignments: []model.FlightSeatAssignment{{Seat: "3B", Code: "R7X6-5JZ3-03J1"}},
+	}}, nil)
+
+	path := dto.SeatPathRequest{FlightPathRequest: dto.FlightPathRequest{FlightNumber: "JT692", Date: "26-07-25"}, Seat: "3B"}
+	var out bytes.Buffer
+	err := uc.RenderSeatQR(t.Context(), path, dto.SeatQRRequest{Payload: model.QRPayloadToken}, &out)
+
+	assert.ErrorIs(t, err, ErrTokenSigningDisabled)
+	assert.Zero(t, out.Len())
+}
+
+func issuedVoucher() *model.IssuedVoucher {
+	return &model.IssuedVoucher{
+		Seat: model.FlightSeatAssignment{ID: 7, FlightAssignmentID: 1, Seat: "3B", Code: "R7X6-5JZ3-03J1"},
+		Assignment: model.FlightAssignment{
+			ID: 1, CrewName: "ApArki", CrewID: "98123", FlightNumber: "JT692", FlightDate: "26-07-25", AircraftType: "ATR",
+		},
+	}
+}
+
+func TestRedeemVoucher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+	redeemed := issuedVoucher()
+	redeemed.Redemption = &model.VoucherRedemption{Station: "CGK"}
+
+	expectTx(mockRepo)
+	// codes are looked up in their printed form however they were typed
+	mockRepo.EXPECT().FindVoucher(gomock.Any(), "R7X6-5JZ3-03J1").Return(issuedVoucher(), nil)
+	mockRepo.EXPECT().RedeemVoucher(gomock.Any(), &model.VoucherRedemption{
+		FlightSeatAssignmentID: 7, Code: "R7X6-5JZ3-03J1", Station: "CGK", RedeemedBy: "gate-agent",
+	}).Return(nil)
+	mockRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.FlightAssignmentEvent) error {
+		assert.Equal(t, model.EventRedeemed, e.Type)
+		assert.Equal(t, uint(1), e.FlightAssignmentID)
+		assert.Equal(t, "gate-agent", e.Actor)
+		assert.Equal(t, "voucher R7X6-5JZ3-03J1 for seat 3B redeemed at CGK", e.Reason)
+		return nil
+	})
+	mockRepo.EXPECT().FindVoucher(gomock.Any(), "R7X6-5JZ3-03J1").Return(redeemed, nil)
+
+	voucher, err := uc.RedeemVoucher(t.Context(), dto.VoucherPathRequest{Code: "r7x6 5jz3 o3jl"}, dto.RedeemRequest{
+		FlightNumber: "JT692", Date: "26-07-25", Seat: "3B", Station: "CGK", Actor: "gate-agent",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.VoucherRedeemed, voucher.State())
+}
+
+func TestRedeemVoucher_Rejected(t *testing.T) {
+	valid := dto.RedeemRequest{FlightNumber: "JT692", Date: "26-07-25", Seat: "3B", Station: "CGK"}
+	tests := map[string]struct {
+		voucher func(v *model.IssuedVoucher)
+		request func(r *dto.RedeemRequest)
+		err     error
+	}{
+		"other seat": {
+			request: func(r *dto.RedeemRequest) { r.Seat = "7C" },
+			err:     ErrVoucherMismatch,
+		},
+		"other flight": {
+			request: func(r *dto.RedeemRequest) { r.FlightNumber, r.Date = "JT693", "27-07-25" },
+			err:     ErrVoucherMismatch,
+		},
+		"already redeemed": {
+			voucher: func(v *model.IssuedVoucher) {
+				v.Redemption = &model.VoucherRedemption{Station: "DPS", RedeemedAt: time.Date(2025, 7, 26, 6, 0, 0, 0, time.UTC)}
+			},
+			err: ErrVoucherRedeemed,
+		},
+		"re-rolled seat": {
+			voucher: func(v *model.IssuedVoucher) { v.Seat.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true} },
+			err:     ErrVoucherRevoked,
+		},
+		"revoked assignment": {
+			voucher: func(v *model.IssuedVoucher) { v.Assignment.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true} },
+			err:     ErrVoucherRevoked,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockRep.NewMockFlightRepository(ctrl)
+			uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+			voucher, request := issuedVoucher(), valid
+			if tt.voucher != nil {
+				tt.voucher(voucher)
+			}
+			if tt.request != nil {
+				tt.request(&request)
+			}
+			expectTx(mockRepo)
+			mockRepo.EXPECT().FindVoucher(gomock.Any(), "R7X6-5JZ3-03J1").Return(voucher, nil)
+
+			_, err := uc.RedeemVoucher(t.Context(), dto.VoucherPathRequest{Code: "R7X6-5JZ3-03J1"}, request)
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestGetVoucher_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRep.NewMockFlightRepository(ctrl)
+	uc := NewVoucherUsecase(mockRepo, mockSvc.NewMockVoucherRenderer(ctrl), nil)
+
+	mockRepo.EXPECT().FindVoucher(gomock.Any(), "R7X6-5JZ3-03J1").Return(nil, nil)
+
+	_, err := uc.GetVoucher(t.Context(), dto.VoucherPathRequest{Code: "R7X6-5JZ3-03J1"})
+	assert.ErrorIs(t, err, ErrVoucherNotFound)
+
+	// a code with a wrong check symbol is never looked up
+	_, err = uc.GetVoucher(t.Context(), dto.VoucherPathRequest{Code: "R7X6-5JZ3-03J2"})
+	assert.ErrorIs(t, err, ErrVoucherNotFound)
+}
+
+func TestVoucherKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer := mockSvc.NewMockVoucherSigner(ctrl)
+	keys := []model.VoucherKey{{ID: "2025-07", PublicKey: make([]byte, 32)}}
+	signer.EXPECT().PublicKeys().Return(keys)
+	assert.Equal(t, keys, NewVoucherUsecase(nil, nil, signer).VoucherKeys())
+
+	// without signing there are no keys to publish
+	assert.Empty(t, NewVoucherUsecase(nil, nil, nil).VoucherKeys())
+}
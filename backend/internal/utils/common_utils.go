@@ -1,6 +1,9 @@
 package utils
 
-import "bookcabin-voucher/internal/model"
+import (
+	"bookcabin-voucher/internal/model"
+	"time"
+)
 
 func ExtractSeats(assignments []model.FlightSeatAssignment) []string {
 	seats := make([]string, 0, len(assignments))
@@ -9,3 +12,25 @@ func ExtractSeats(assignments []model.FlightSeatAssignment) []string {
 	}
 	return seats
 }
+
+// MergeSeats returns the seats of both lists without duplicates, keeping first-seen order.
+func MergeSeats(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, seat := range append(append([]string(nil), a...), b...) {
+		if !seen[seat] {
+			seen[seat] = true
+			merged = append(merged, seat)
+		}
+	}
+	return merged
+}
+
+// ISODate turns a DD-MM-YY flight date into YYYY-MM-DD, or "" when it is not a valid date.
+// Years are always taken as 20YY, matching the backfill in the migration.
+func ISODate(date string) string {
+	if _, err := time.Parse("02-01-06", date); err != nil {
+		return ""
+	}
+	return "20" + date[6:8] + "-" + date[3:5] + "-" + date[0:2]
+}
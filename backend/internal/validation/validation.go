@@ -1,30 +1,60 @@
 package validation
 
 import (
-	"bookcabin-voucher/internal/model"
+	"bookcabin-voucher/internal/service"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"reflect"
 	"regexp"
+	"strings"
 )
 
-func RegisterValidators() {
+// RegisterValidators installs the custom binding tags, resolving aircraft against registry.
+func RegisterValidators(registry service.AircraftRegistry) {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(FieldName)
 		v.RegisterValidation("flight_number", FlightNumberValidator)
-		v.RegisterValidation("aircraft_enum", AircraftEnumValidator)
+		v.RegisterValidation("aircraft_enum", AircraftEnumValidator(registry))
+		v.RegisterValidation("seat_strategy", SeatStrategyValidator)
+		v.RegisterValidation("voucher_code", VoucherCodeValidator)
 	}
 }
 
-// AircraftEnumValidator checks if Aircraft is one of the allowed values
-func AircraftEnumValidator(fl validator.FieldLevel) bool {
-	aircraft := model.AircraftType(fl.Field().String())
-	switch aircraft {
-	case model.ATR, model.Airbus320, model.Boeing737Max:
-		return true
-	default:
-		return false
+// AircraftEnumValidator checks if Aircraft is a known aircraft type or alias
+func AircraftEnumValidator(registry service.AircraftRegistry) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		_, ok := registry.Resolve(fl.Field().String())
+		return ok
 	}
 }
 
+// SeatStrategyValidator checks if the seat strategy is registered in the allocator
+func SeatStrategyValidator(fl validator.FieldLevel) bool {
+	_, ok := service.LookupStrategy(fl.Field().String())
+	return ok
+}
+
+// VoucherCodeValidator checks that a voucher code is well formed and its check symbol matches
+func VoucherCodeValidator(fl validator.FieldLevel) bool {
+	_, ok := service.NormalizeVoucherCode(fl.Field().String())
+	return ok
+}
+
+// FieldName reports fields under the name the client sent them with, taken
+// from the json, uri or form tag, so validation errors can point at them.
+func FieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "uri", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
 var flightNumberRegex = regexp.MustCompile(`^[A-Z]{2}\d{1,4}$`)
 
 // FlightNumberValidator checks if flightNumber following a correct pattern
@@ -12,10 +12,11 @@ package mocks
 import (
 	dto "bookcabin-voucher/internal/dto"
 	model "bookcabin-voucher/internal/model"
+	repository "bookcabin-voucher/internal/repository"
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
-	gorm "gorm.io/gorm"
 )
 
 // MockFlightRepository is a mock of FlightRepository interface.
@@ -42,118 +43,248 @@ func (m *MockFlightRepository) EXPECT() *MockFlightRepositoryMockRecorder {
 	return m.recorder
 }
 
-// BeginTx mocks base method.
-func (m *MockFlightRepository) BeginTx() *gorm.DB {
+// AppendEvent mocks base method.
+func (m *MockFlightRepository) AppendEvent(ctx context.Context, event *model.FlightAssignmentEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx")
-	ret0, _ := ret[0].(*gorm.DB)
+	ret := m.ctrl.Call(m, "AppendEvent", ctx, event)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockFlightRepositoryMockRecorder) BeginTx() *gomock.Call {
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockFlightRepositoryMockRecorder) AppendEvent(ctx, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockFlightRepository)(nil).BeginTx))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockFlightRepository)(nil).AppendEvent), ctx, event)
 }
 
-// BulkCreateSeatAssignmentsTx mocks base method.
-func (m *MockFlightRepository) BulkCreateSeatAssignmentsTx(tx *gorm.DB, seats []model.FlightSeatAssignment) error {
+// BulkCreateSeatAssignments mocks base method.
+func (m *MockFlightRepository) BulkCreateSeatAssignments(ctx context.Context, seats []model.FlightSeatAssignment) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkCreateSeatAssignmentsTx", tx, seats)
+	ret := m.ctrl.Call(m, "BulkCreateSeatAssignments", ctx, seats)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// BulkCreateSeatAssignmentsTx indicates an expected call of BulkCreateSeatAssignmentsTx.
-func (mr *MockFlightRepositoryMockRecorder) BulkCreateSeatAssignmentsTx(tx, seats any) *gomock.Call {
+// BulkCreateSeatAssignments indicates an expected call of BulkCreateSeatAssignments.
+func (mr *MockFlightRepositoryMockRecorder) BulkCreateSeatAssignments(ctx, seats any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateSeatAssignmentsTx", reflect.TypeOf((*MockFlightRepository)(nil).BulkCreateSeatAssignmentsTx), tx, seats)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateSeatAssignments", reflect.TypeOf((*MockFlightRepository)(nil).BulkCreateSeatAssignments), ctx, seats)
 }
 
 // CountByFlightAndDate mocks base method.
-func (m *MockFlightRepository) CountByFlightAndDate(flightNumber, date string) int64 {
+func (m *MockFlightRepository) CountByFlightAndDate(ctx context.Context, flightNumber, date string) int64 {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountByFlightAndDate", flightNumber, date)
+	ret := m.ctrl.Call(m, "CountByFlightAndDate", ctx, flightNumber, date)
 	ret0, _ := ret[0].(int64)
 	return ret0
 }
 
 // CountByFlightAndDate indicates an expected call of CountByFlightAndDate.
-func (mr *MockFlightRepositoryMockRecorder) CountByFlightAndDate(flightNumber, date any) *gomock.Call {
+func (mr *MockFlightRepositoryMockRecorder) CountByFlightAndDate(ctx, flightNumber, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFlightAndDate", reflect.TypeOf((*MockFlightRepository)(nil).CountByFlightAndDate), ctx, flightNumber, date)
+}
+
+// Create mocks base method.
+func (m *MockFlightRepository) Create(ctx context.Context, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, assignment)
+	ret0, _ := ret[0].(*model.FlightAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFlightRepositoryMockRecorder) Create(ctx, assignment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFlightAndDate", reflect.TypeOf((*MockFlightRepository)(nil).CountByFlightAndDate), flightNumber, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFlightRepository)(nil).Create), ctx, assignment)
 }
 
-// CountByFlightAndDateTx mocks base method.
-func (m *MockFlightRepository) CountByFlightAndDateTx(tx *gorm.DB, flightNumber, date string) int64 {
+// DeleteSeatsByFilter mocks base method.
+func (m *MockFlightRepository) DeleteSeatsByFilter(ctx context.Context, filter dto.FlightFilter, actor string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountByFlightAndDateTx", tx, flightNumber, date)
+	ret := m.ctrl.Call(m, "DeleteSeatsByFilter", ctx, filter, actor)
 	ret0, _ := ret[0].(int64)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CountByFlightAndDateTx indicates an expected call of CountByFlightAndDateTx.
-func (mr *MockFlightRepositoryMockRecorder) CountByFlightAndDateTx(tx, flightNumber, date any) *gomock.Call {
+// DeleteSeatsByFilter indicates an expected call of DeleteSeatsByFilter.
+func (mr *MockFlightRepositoryMockRecorder) DeleteSeatsByFilter(ctx, filter, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFlightAndDateTx", reflect.TypeOf((*MockFlightRepository)(nil).CountByFlightAndDateTx), tx, flightNumber, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeatsByFilter", reflect.TypeOf((*MockFlightRepository)(nil).DeleteSeatsByFilter), ctx, filter, actor)
 }
 
-// CreateTx mocks base method.
-func (m *MockFlightRepository) CreateTx(tx *gorm.DB, assignment *model.FlightAssignment) (*model.FlightAssignment, error) {
+// FindOccupiedSeats mocks base method.
+func (m *MockFlightRepository) FindOccupiedSeats(ctx context.Context, flightNumber, date string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTx", tx, assignment)
-	ret0, _ := ret[0].(*model.FlightAssignment)
+	ret := m.ctrl.Call(m, "FindOccupiedSeats", ctx, flightNumber, date)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTx indicates an expected call of CreateTx.
-func (mr *MockFlightRepositoryMockRecorder) CreateTx(tx, assignment any) *gomock.Call {
+// FindOccupiedSeats indicates an expected call of FindOccupiedSeats.
+func (mr *MockFlightRepositoryMockRecorder) FindOccupiedSeats(ctx, flightNumber, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockFlightRepository)(nil).CreateTx), tx, assignment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOccupiedSeats", reflect.TypeOf((*MockFlightRepository)(nil).FindOccupiedSeats), ctx, flightNumber, date)
 }
 
-// DeleteSeatsByFilterTx mocks base method.
-func (m *MockFlightRepository) DeleteSeatsByFilterTx(tx *gorm.DB, filter dto.FlightFilter) (int64, error) {
+// FindVoucher mocks base method.
+func (m *MockFlightRepository) FindVoucher(ctx context.Context, code string) (*model.IssuedVoucher, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSeatsByFilterTx", tx, filter)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "FindVoucher", ctx, code)
+	ret0, _ := ret[0].(*model.IssuedVoucher)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteSeatsByFilterTx indicates an expected call of DeleteSeatsByFilterTx.
-func (mr *MockFlightRepositoryMockRecorder) DeleteSeatsByFilterTx(tx, filter any) *gomock.Call {
+// FindVoucher indicates an expected call of FindVoucher.
+func (mr *MockFlightRepositoryMockRecorder) FindVoucher(ctx, code any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeatsByFilterTx", reflect.TypeOf((*MockFlightRepository)(nil).DeleteSeatsByFilterTx), tx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVoucher", reflect.TypeOf((*MockFlightRepository)(nil).FindVoucher), ctx, code)
 }
 
 // GetByFilter mocks base method.
-func (m *MockFlightRepository) GetByFilter(filter dto.FlightFilter) ([]model.FlightAssignment, error) {
+func (m *MockFlightRepository) GetByFilter(ctx context.Context, filter dto.FlightFilter) ([]model.FlightAssignment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByFilter", filter)
+	ret := m.ctrl.Call(m, "GetByFilter", ctx, filter)
 	ret0, _ := ret[0].([]model.FlightAssignment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByFilter indicates an expected call of GetByFilter.
-func (mr *MockFlightRepositoryMockRecorder) GetByFilter(filter any) *gomock.Call {
+func (mr *MockFlightRepositoryMockRecorder) GetByFilter(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByFilter", reflect.TypeOf((*MockFlightRepository)(nil).GetByFilter), ctx, filter)
+}
+
+// GetSeatInventory mocks base method.
+func (m *MockFlightRepository) GetSeatInventory(ctx context.Context, flightNumber, date string) ([]model.FlightSeatInventory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatInventory", ctx, flightNumber, date)
+	ret0, _ := ret[0].([]model.FlightSeatInventory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatInventory indicates an expected call of GetSeatInventory.
+func (mr *MockFlightRepositoryMockRecorder) GetSeatInventory(ctx, flightNumber, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByFilter", reflect.TypeOf((*MockFlightRepository)(nil).GetByFilter), filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatInventory", reflect.TypeOf((*MockFlightRepository)(nil).GetSeatInventory), ctx, flightNumber, date)
 }
 
-// GetByFilterTx mocks base method.
-func (m *MockFlightRepository) GetByFilterTx(tx *gorm.DB, filter dto.FlightFilter) ([]model.FlightAssignment, error) {
+// ListAssignments mocks base method.
+func (m *MockFlightRepository) ListAssignments(ctx context.Context, filter dto.AssignmentFilter) ([]model.FlightAssignment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByFilterTx", tx, filter)
+	ret := m.ctrl.Call(m, "ListAssignments", ctx, filter)
 	ret0, _ := ret[0].([]model.FlightAssignment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByFilterTx indicates an expected call of GetByFilterTx.
-func (mr *MockFlightRepositoryMockRecorder) GetByFilterTx(tx, filter any) *gomock.Call {
+// ListAssignments indicates an expected call of ListAssignments.
+func (mr *MockFlightRepositoryMockRecorder) ListAssignments(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssignments", reflect.TypeOf((*MockFlightRepository)(nil).ListAssignments), ctx, filter)
+}
+
+// ListEvents mocks base method.
+func (m *MockFlightRepository) ListEvents(ctx context.Context, flightNumber, date string) ([]model.FlightAssignmentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, flightNumber, date)
+	ret0, _ := ret[0].([]model.FlightAssignmentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockFlightRepositoryMockRecorder) ListEvents(ctx, flightNumber, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockFlightRepository)(nil).ListEvents), ctx, flightNumber, date)
+}
+
+// LockFlight mocks base method.
+func (m *MockFlightRepository) LockFlight(ctx context.Context, flightNumber, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockFlight", ctx, flightNumber, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockFlight indicates an expected call of LockFlight.
+func (mr *MockFlightRepositoryMockRecorder) LockFlight(ctx, flightNumber, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockFlight", reflect.TypeOf((*MockFlightRepository)(nil).LockFlight), ctx, flightNumber, date)
+}
+
+// RedeemVoucher mocks base method.
+func (m *MockFlightRepository) RedeemVoucher(ctx context.Context, redemption *model.VoucherRedemption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemVoucher", ctx, redemption)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeemVoucher indicates an expected call of RedeemVoucher.
+func (mr *MockFlightRepositoryMockRecorder) RedeemVoucher(ctx, redemption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemVoucher", reflect.TypeOf((*MockFlightRepository)(nil).RedeemVoucher), ctx, redemption)
+}
+
+// RevokeAssignment mocks base method.
+func (m *MockFlightRepository) RevokeAssignment(ctx context.Context, assignmentID uint, actor, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAssignment", ctx, assignmentID, actor, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAssignment indicates an expected call of RevokeAssignment.
+func (mr *MockFlightRepositoryMockRecorder) RevokeAssignment(ctx, assignmentID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAssignment", reflect.TypeOf((*MockFlightRepository)(nil).RevokeAssignment), ctx, assignmentID, actor, reason)
+}
+
+// RevokeSeats mocks base method.
+func (m *MockFlightRepository) RevokeSeats(ctx context.Context, assignmentID uint, seats []string, actor, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSeats", ctx, assignmentID, seats, actor, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSeats indicates an expected call of RevokeSeats.
+func (mr *MockFlightRepositoryMockRecorder) RevokeSeats(ctx, assignmentID, seats, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSeats", reflect.TypeOf((*MockFlightRepository)(nil).RevokeSeats), ctx, assignmentID, seats, actor, reason)
+}
+
+// SetSeatStatus mocks base method.
+func (m *MockFlightRepository) SetSeatStatus(ctx context.Context, flightNumber, date string, seats []string, status model.SeatStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSeatStatus", ctx, flightNumber, date, seats, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSeatStatus indicates an expected call of SetSeatStatus.
+func (mr *MockFlightRepositoryMockRecorder) SetSeatStatus(ctx, flightNumber, date, seats, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSeatStatus", reflect.TypeOf((*MockFlightRepository)(nil).SetSeatStatus), ctx, flightNumber, date, seats, status)
+}
+
+// WithinTx mocks base method.
+func (m *MockFlightRepository) WithinTx(ctx context.Context, fn func(repository.FlightRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockFlightRepositoryMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByFilterTx", reflect.TypeOf((*MockFlightRepository)(nil).GetByFilterTx), tx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockFlightRepository)(nil).WithinTx), ctx, fn)
 }
@@ -11,11 +11,65 @@ package mocks
 
 import (
 	model "bookcabin-voucher/internal/model"
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockAircraftRegistry is a mock of AircraftRegistry interface.
+type MockAircraftRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockAircraftRegistryMockRecorder
+	isgomock struct{}
+}
+
+// MockAircraftRegistryMockRecorder is the mock recorder for MockAircraftRegistry.
+type MockAircraftRegistryMockRecorder struct {
+	mock *MockAircraftRegistry
+}
+
+// NewMockAircraftRegistry creates a new mock instance.
+func NewMockAircraftRegistry(ctrl *gomock.Controller) *MockAircraftRegistry {
+	mock := &MockAircraftRegistry{ctrl: ctrl}
+	mock.recorder = &MockAircraftRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAircraftRegistry) EXPECT() *MockAircraftRegistryMockRecorder {
+	return m.recorder
+}
+
+// Aircraft mocks base method.
+func (m *MockAircraftRegistry) Aircraft() []model.AircraftInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aircraft")
+	ret0, _ := ret[0].([]model.AircraftInfo)
+	return ret0
+}
+
+// Aircraft indicates an expected call of Aircraft.
+func (mr *MockAircraftRegistryMockRecorder) Aircraft() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aircraft", reflect.TypeOf((*MockAircraftRegistry)(nil).Aircraft))
+}
+
+// Resolve mocks base method.
+func (m *MockAircraftRegistry) Resolve(name string) (model.AircraftInfo, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", name)
+	ret0, _ := ret[0].(model.AircraftInfo)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockAircraftRegistryMockRecorder) Resolve(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockAircraftRegistry)(nil).Resolve), name)
+}
+
 // MockSeatAllocator is a mock of SeatAllocator interface.
 type MockSeatAllocator struct {
 	ctrl     *gomock.Controller
@@ -40,17 +94,46 @@ func (m *MockSeatAllocator) EXPECT() *MockSeatAllocatorMockRecorder {
 	return m.recorder
 }
 
+// Aircraft mocks base method.
+func (m *MockSeatAllocator) Aircraft() []model.AircraftInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aircraft")
+	ret0, _ := ret[0].([]model.AircraftInfo)
+	return ret0
+}
+
+// Aircraft indicates an expected call of Aircraft.
+func (mr *MockSeatAllocatorMockRecorder) Aircraft() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aircraft", reflect.TypeOf((*MockSeatAllocator)(nil).Aircraft))
+}
+
 // GenerateSeats mocks base method.
-func (m *MockSeatAllocator) GenerateSeats(aircraft model.AircraftType, count int, existingSeats []string) ([]string, error) {
+func (m *MockSeatAllocator) GenerateSeats(ctx context.Context, aircraft model.AircraftType, count int, existingSeats []string, strategy string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateSeats", aircraft, count, existingSeats)
+	ret := m.ctrl.Call(m, "GenerateSeats", ctx, aircraft, count, existingSeats, strategy)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateSeats indicates an expected call of GenerateSeats.
-func (mr *MockSeatAllocatorMockRecorder) GenerateSeats(aircraft, count, existingSeats any) *gomock.Call {
+func (mr *MockSeatAllocatorMockRecorder) GenerateSeats(ctx, aircraft, count, existingSeats, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSeats", reflect.TypeOf((*MockSeatAllocator)(nil).GenerateSeats), ctx, aircraft, count, existingSeats, strategy)
+}
+
+// Resolve mocks base method.
+func (m *MockSeatAllocator) Resolve(name string) (model.AircraftInfo, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", name)
+	ret0, _ := ret[0].(model.AircraftInfo)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockSeatAllocatorMockRecorder) Resolve(name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSeats", reflect.TypeOf((*MockSeatAllocator)(nil).GenerateSeats), aircraft, count, existingSeats)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockSeatAllocator)(nil).Resolve), name)
 }
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/voucher_renderer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/voucher_renderer.go -destination=mocks/service/voucher_renderer_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "bookcabin-voucher/internal/model"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVoucherRenderer is a mock of VoucherRenderer interface.
+type MockVoucherRenderer struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherRendererMockRecorder
+	isgomock struct{}
+}
+
+// MockVoucherRendererMockRecorder is the mock recorder for MockVoucherRenderer.
+type MockVoucherRendererMockRecorder struct {
+	mock *MockVoucherRenderer
+}
+
+// NewMockVoucherRenderer creates a new mock instance.
+func NewMockVoucherRenderer(ctrl *gomock.Controller) *MockVoucherRenderer {
+	mock := &MockVoucherRenderer{ctrl: ctrl}
+	mock.recorder = &MockVoucherRendererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucherRenderer) EXPECT() *MockVoucherRendererMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockVoucherRenderer) Render(w io.Writer, vouchers []model.Voucher) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", w, vouchers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockVoucherRendererMockRecorder) Render(w, vouchers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockVoucherRenderer)(nil).Render), w, vouchers)
+}
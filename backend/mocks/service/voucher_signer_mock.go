@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/voucher_signer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/voucher_signer.go -destination=mocks/service/voucher_signer_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "bookcabin-voucher/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVoucherSigner is a mock of VoucherSigner interface.
+type MockVoucherSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockVoucherSignerMockRecorder is the mock recorder for MockVoucherSigner.
+type MockVoucherSignerMockRecorder struct {
+	mock *MockVoucherSigner
+}
+
+// NewMockVoucherSigner creates a new mock instance.
+func NewMockVoucherSigner(ctrl *gomock.Controller) *MockVoucherSigner {
+	mock := &MockVoucherSigner{ctrl: ctrl}
+	mock.recorder = &MockVoucherSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucherSigner) EXPECT() *MockVoucherSignerMockRecorder {
+	return m.recorder
+}
+
+// PublicKeys mocks base method.
+func (m *MockVoucherSigner) PublicKeys() []model.VoucherKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].([]model.VoucherKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockVoucherSignerMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockVoucherSigner)(nil).PublicKeys))
+}
+
+// Sign mocks base method.
+func (m *MockVoucherSigner) Sign(voucher model.Voucher) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", voucher)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockVoucherSignerMockRecorder) Sign(voucher any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockVoucherSigner)(nil).Sign), voucher)
+}
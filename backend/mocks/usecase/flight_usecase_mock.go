@@ -12,6 +12,7 @@ package mocks
 import (
 	dto "bookcabin-voucher/internal/dto"
 	model "bookcabin-voucher/internal/model"
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -42,30 +43,134 @@ func (m *MockFlightUsecase) EXPECT() *MockFlightUsecaseMockRecorder {
 }
 
 // CheckFlightExists mocks base method.
-func (m *MockFlightUsecase) CheckFlightExists(request dto.CheckFlightRequest) bool {
+func (m *MockFlightUsecase) CheckFlightExists(ctx context.Context, request dto.CheckFlightRequest) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckFlightExists", request)
+	ret := m.ctrl.Call(m, "CheckFlightExists", ctx, request)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // CheckFlightExists indicates an expected call of CheckFlightExists.
-func (mr *MockFlightUsecaseMockRecorder) CheckFlightExists(request any) *gomock.Call {
+func (mr *MockFlightUsecaseMockRecorder) CheckFlightExists(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFlightExists", reflect.TypeOf((*MockFlightUsecase)(nil).CheckFlightExists), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFlightExists", reflect.TypeOf((*MockFlightUsecase)(nil).CheckFlightExists), ctx, request)
 }
 
 // GenerateAndAssignSeats mocks base method.
-func (m *MockFlightUsecase) GenerateAndAssignSeats(request dto.GenerateRequest) (*model.FlightAssignment, error) {
+func (m *MockFlightUsecase) GenerateAndAssignSeats(ctx context.Context, request dto.GenerateRequest) (*model.FlightAssignment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateAndAssignSeats", request)
+	ret := m.ctrl.Call(m, "GenerateAndAssignSeats", ctx, request)
 	ret0, _ := ret[0].(*model.FlightAssignment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateAndAssignSeats indicates an expected call of GenerateAndAssignSeats.
-func (mr *MockFlightUsecaseMockRecorder) GenerateAndAssignSeats(request any) *gomock.Call {
+func (mr *MockFlightUsecaseMockRecorder) GenerateAndAssignSeats(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAndAssignSeats", reflect.TypeOf((*MockFlightUsecase)(nil).GenerateAndAssignSeats), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAndAssignSeats", reflect.TypeOf((*MockFlightUsecase)(nil).GenerateAndAssignSeats), ctx, request)
+}
+
+// GetAssignment mocks base method.
+func (m *MockFlightUsecase) GetAssignment(ctx context.Context, request dto.FlightPathRequest) (*model.FlightAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignment", ctx, request)
+	ret0, _ := ret[0].(*model.FlightAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignment indicates an expected call of GetAssignment.
+func (mr *MockFlightUsecaseMockRecorder) GetAssignment(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignment", reflect.TypeOf((*MockFlightUsecase)(nil).GetAssignment), ctx, request)
+}
+
+// GetHistory mocks base method.
+func (m *MockFlightUsecase) GetHistory(ctx context.Context, request dto.FlightPathRequest) ([]model.FlightAssignmentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, request)
+	ret0, _ := ret[0].([]model.FlightAssignmentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockFlightUsecaseMockRecorder) GetHistory(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockFlightUsecase)(nil).GetHistory), ctx, request)
+}
+
+// GetSeatInventory mocks base method.
+func (m *MockFlightUsecase) GetSeatInventory(ctx context.Context, request dto.SeatInventoryRequest) (*model.SeatInventory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatInventory", ctx, request)
+	ret0, _ := ret[0].(*model.SeatInventory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatInventory indicates an expected call of GetSeatInventory.
+func (mr *MockFlightUsecaseMockRecorder) GetSeatInventory(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatInventory", reflect.TypeOf((*MockFlightUsecase)(nil).GetSeatInventory), ctx, request)
+}
+
+// ListAircraft mocks base method.
+func (m *MockFlightUsecase) ListAircraft(ctx context.Context) []model.AircraftInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAircraft", ctx)
+	ret0, _ := ret[0].([]model.AircraftInfo)
+	return ret0
+}
+
+// ListAircraft indicates an expected call of ListAircraft.
+func (mr *MockFlightUsecaseMockRecorder) ListAircraft(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAircraft", reflect.TypeOf((*MockFlightUsecase)(nil).ListAircraft), ctx)
+}
+
+// ListAssignments mocks base method.
+func (m *MockFlightUsecase) ListAssignments(ctx context.Context, request dto.ListAssignmentsRequest) ([]model.FlightAssignment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssignments", ctx, request)
+	ret0, _ := ret[0].([]model.FlightAssignment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAssignments indicates an expected call of ListAssignments.
+func (mr *MockFlightUsecaseMockRecorder) ListAssignments(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssignments", reflect.TypeOf((*MockFlightUsecase)(nil).ListAssignments), ctx, request)
+}
+
+// RevokeAssignment mocks base method.
+func (m *MockFlightUsecase) RevokeAssignment(ctx context.Context, flight dto.FlightPathRequest, request dto.RevokeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAssignment", ctx, flight, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAssignment indicates an expected call of RevokeAssignment.
+func (mr *MockFlightUsecaseMockRecorder) RevokeAssignment(ctx, flight, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAssignment", reflect.TypeOf((*MockFlightUsecase)(nil).RevokeAssignment), ctx, flight, request)
+}
+
+// RevokeSeat mocks base method.
+func (m *MockFlightUsecase) RevokeSeat(ctx context.Context, seat dto.SeatPathRequest, request dto.RevokeRequest) (*model.FlightAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSeat", ctx, seat, request)
+	ret0, _ := ret[0].(*model.FlightAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSeat indicates an expected call of RevokeSeat.
+func (mr *MockFlightUsecaseMockRecorder) RevokeSeat(ctx, seat, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSeat", reflect.TypeOf((*MockFlightUsecase)(nil).RevokeSeat), ctx, seat, request)
 }
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/voucher_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/voucher_usecase.go -destination=mocks/usecase/voucher_usecase_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	dto "bookcabin-voucher/internal/dto"
+	model "bookcabin-voucher/internal/model"
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVoucherUsecase is a mock of VoucherUsecase interface.
+type MockVoucherUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockVoucherUsecaseMockRecorder is the mock recorder for MockVoucherUsecase.
+type MockVoucherUsecaseMockRecorder struct {
+	mock *MockVoucherUsecase
+}
+
+// NewMockVoucherUsecase creates a new mock instance.
+func NewMockVoucherUsecase(ctrl *gomock.Controller) *MockVoucherUsecase {
+	mock := &MockVoucherUsecase{ctrl: ctrl}
+	mock.recorder = &MockVoucherUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucherUsecase) EXPECT() *MockVoucherUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetVoucher mocks base method.
+func (m *MockVoucherUsecase) GetVoucher(ctx context.Context, request dto.VoucherPathRequest) (*model.IssuedVoucher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVoucher", ctx, request)
+	ret0, _ := ret[0].(*model.IssuedVoucher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVoucher indicates an expected call of GetVoucher.
+func (mr *MockVoucherUsecaseMockRecorder) GetVoucher(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVoucher", reflect.TypeOf((*MockVoucherUsecase)(nil).GetVoucher), ctx, request)
+}
+
+// RedeemVoucher mocks base method.
+func (m *MockVoucherUsecase) RedeemVoucher(ctx context.Context, voucher dto.VoucherPathRequest, request dto.RedeemRequest) (*model.IssuedVoucher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemVoucher", ctx, voucher, request)
+	ret0, _ := ret[0].(*model.IssuedVoucher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemVoucher indicates an expected call of RedeemVoucher.
+func (mr *MockVoucherUsecaseMockRecorder) RedeemVoucher(ctx, voucher, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemVoucher", reflect.TypeOf((*MockVoucherUsecase)(nil).RedeemVoucher), ctx, voucher, request)
+}
+
+// RenderSeatQR mocks base method.
+func (m *MockVoucherUsecase) RenderSeatQR(ctx context.Context, seat dto.SeatPathRequest, request dto.SeatQRRequest, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderSeatQR", ctx, seat, request, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenderSeatQR indicates an expected call of RenderSeatQR.
+func (mr *MockVoucherUsecaseMockRecorder) RenderSeatQR(ctx, seat, request, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderSeatQR", reflect.TypeOf((*MockVoucherUsecase)(nil).RenderSeatQR), ctx, seat, request, w)
+}
+
+// RenderVouchers mocks base method.
+func (m *MockVoucherUsecase) RenderVouchers(ctx context.Context, request dto.FlightPathRequest, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderVouchers", ctx, request, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenderVouchers indicates an expected call of RenderVouchers.
+func (mr *MockVoucherUsecaseMockRecorder) RenderVouchers(ctx, request, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderVouchers", reflect.TypeOf((*MockVoucherUsecase)(nil).RenderVouchers), ctx, request, w)
+}
+
+// VoucherKeys mocks base method.
+func (m *MockVoucherUsecase) VoucherKeys() []model.VoucherKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoucherKeys")
+	ret0, _ := ret[0].([]model.VoucherKey)
+	return ret0
+}
+
+// VoucherKeys indicates an expected call of VoucherKeys.
+func (mr *MockVoucherUsecaseMockRecorder) VoucherKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoucherKeys", reflect.TypeOf((*MockVoucherUsecase)(nil).VoucherKeys))
+}
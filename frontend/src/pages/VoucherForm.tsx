@@ -1,4 +1,4 @@
-import React, { useState } from "react";
+import React, { useEffect, useState } from "react";
 import {
   Box,
   Button,
@@ -11,12 +11,22 @@ import {
 } from "@mui/material";
 import { useFormik } from "formik";
 import { getGenerateRequestValidationSchema } from "../utils/validators";
-import { handleVoucherSubmit } from "../services/handleSubmit";
+import { fetchAircraftTypes, handleVoucherSubmit } from "../services/handleSubmit";
 import { AIRCRAFT_TYPES } from "../constant/aircraft";
+import type { Voucher } from "../types/api";
 
 const VoucherForm: React.FC = () => {
   const [seats, setSeats] = useState<string[] | null>(null);
   const [selectedSeats, setSelectedSeats] = useState<string[]>([]);
+  const [vouchers, setVouchers] = useState<Voucher[]>([]);
+  const [aircraftTypes, setAircraftTypes] = useState<string[]>(AIRCRAFT_TYPES);
+
+  useEffect(() => {
+    // keep the built-in list if the backend cannot be reached
+    fetchAircraftTypes()
+      .then((types) => types.length > 0 && setAircraftTypes(types))
+      .catch(() => undefined);
+  }, []);
 
   const formik = useFormik({
     initialValues: {
@@ -26,7 +36,7 @@ const VoucherForm: React.FC = () => {
       date: "",
       aircraft: "",
     },
-    validationSchema: getGenerateRequestValidationSchema(),
+    validationSchema: getGenerateRequestValidationSchema(aircraftTypes),
     onSubmit: async (values) => {
       // Add selected seats to the request body if any are selected
       const requestData = {
@@ -34,7 +44,7 @@ const VoucherForm: React.FC = () => {
         ...(selectedSeats.length > 0 && { seats: selectedSeats })
       };
 
-      await handleVoucherSubmit(requestData, setSeats); // enqueueSnackbar is handled internally
+      await handleVoucherSubmit(requestData, setSeats, setVouchers); // enqueueSnackbar is handled internally
 
       setSelectedSeats([]);
     },
@@ -115,7 +125,7 @@ const VoucherForm: React.FC = () => {
             <MenuItem value="">
               <em>None</em>
             </MenuItem>
-            {AIRCRAFT_TYPES.map((type) => (
+            {aircraftTypes.map((type) => (
               <MenuItem key={type} value={type}>
                 {type}
               </MenuItem>
@@ -180,6 +190,28 @@ const VoucherForm: React.FC = () => {
             Assigned Seats:
           </Typography>
           <Typography>{seats.join(", ")}</Typography>
+          {vouchers.map((voucher) => (
+              <Box key={voucher.seat} mt={1}>
+                <Typography variant="body2" fontFamily="monospace">
+                  {voucher.seat}: {voucher.code}
+                </Typography>
+                <img
+                    src={`/api/flights/${encodeURIComponent(formik.values.flightNumber)}/${encodeURIComponent(formik.values.date)}/assignment/seats/${encodeURIComponent(voucher.seat)}/qr?format=svg&payload=${voucher.token ? "token" : "code"}`}
+                    alt={`QR code for seat ${voucher.seat}`}
+                    width={128}
+                    height={128}
+                />
+              </Box>
+          ))}
+          <Button
+              variant="outlined"
+              href={`/api/flights/${encodeURIComponent(formik.values.flightNumber)}/${encodeURIComponent(formik.values.date)}/voucher.pdf`}
+              target="_blank"
+              rel="noopener"
+              sx={{ mt: 2 }}
+          >
+            Print Vouchers (PDF)
+          </Button>
         </Box>
       )}
     </Box>
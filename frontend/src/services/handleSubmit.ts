@@ -1,10 +1,36 @@
 import axios from "axios";
-import type { GenerateRequest } from "../types/api";
+import type { AircraftInfo, GenerateRequest, GenerateResponse, Voucher } from "../types/api";
 import { enqueueSnackbar } from "notistack";
 
+export const fetchAircraftTypes = async (): Promise<string[]> => {
+  const res = await axios.get<{ aircraft: AircraftInfo[] }>("/api/aircraft");
+  return res.data.aircraft.map((a) => a.type);
+};
+
+const MAX_ATTEMPTS = 3;
+
+// postWithRetry repeats a request that got no response, e.g. on a dropped
+// connection, with the same headers. Server responses are never retried.
+const postWithRetry = async <T>(
+  url: string,
+  data: unknown,
+  headers: Record<string, string>
+) => {
+  for (let attempt = 1; ; attempt++) {
+    try {
+      return await axios.post<T>(url, data, { headers });
+    } catch (error: unknown) {
+      if (attempt >= MAX_ATTEMPTS || !axios.isAxiosError(error) || error.response) {
+        throw error;
+      }
+    }
+  }
+};
+
 export const handleVoucherSubmit = async (
   values: GenerateRequest,
-  setSeats: React.Dispatch<React.SetStateAction<string[] | null>>
+  setSeats: React.Dispatch<React.SetStateAction<string[] | null>>,
+  setVouchers: React.Dispatch<React.SetStateAction<Voucher[]>>
 ): Promise<void> => {
   try {
     if (!values.seats || values.seats.length === 0) {
@@ -21,8 +47,12 @@ export const handleVoucherSubmit = async (
       }
     }
 
-    const genRes = await axios.post("/api/generate", values);
+    // one key per submission, so a retried request cannot issue or re-roll twice
+    const genRes = await postWithRetry<GenerateResponse>("/api/generate", values, {
+      "Idempotency-Key": crypto.randomUUID(),
+    });
     setSeats(genRes.data.seats);
+    setVouchers(genRes.data.vouchers ?? []);
 
     enqueueSnackbar(`Vouchers generated! Seats: ${genRes.data.seats.join(", ")}`, {
       variant: "success",
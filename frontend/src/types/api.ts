@@ -5,4 +5,26 @@ export interface GenerateRequest {
   date: string;
   aircraft: string;
   seats?: string[];
+  strategy?: string;
+  count?: number;
+}
+
+export interface Voucher {
+  seat: string;
+  code: string;
+  token?: string;
+}
+
+export interface GenerateResponse {
+  success: boolean;
+  seats: string[];
+  vouchers: Voucher[];
+}
+
+export interface AircraftInfo {
+  type: string;
+  aliases: string[];
+  seats: number;
+  defaultVouchers: number;
+  maxVouchers: number;
 }
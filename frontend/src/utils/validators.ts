@@ -1,7 +1,7 @@
 import * as Yup from "yup";
 import { AIRCRAFT_TYPES } from "../constant/aircraft.ts";
 
-export const getGenerateRequestValidationSchema = () => {
+export const getGenerateRequestValidationSchema = (aircraftTypes: string[] = AIRCRAFT_TYPES) => {
   return Yup.object().shape({
     name: Yup.string().required("Crew name is required"),
     id: Yup.string().required("Crew ID is required"),
@@ -13,7 +13,7 @@ export const getGenerateRequestValidationSchema = () => {
       .required("Required"),
     aircraft: Yup.string()
       .required("Aircraft type is required")
-      .oneOf(AIRCRAFT_TYPES, "Invalid aircraft")
+      .oneOf(aircraftTypes, "Invalid aircraft")
       .required("Required"),
   });
 };